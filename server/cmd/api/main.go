@@ -10,10 +10,20 @@ import (
 	"cloud.google.com/go/firestore"
 	"cloud.google.com/go/pubsub"
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/scosta/goal-app/internal/auth"
+	"github.com/scosta/goal-app/internal/clock"
 	"github.com/scosta/goal-app/internal/handlers"
+	"github.com/scosta/goal-app/internal/metrics"
+	"github.com/scosta/goal-app/internal/notify"
+	"github.com/scosta/goal-app/internal/observability"
 	apppubsub "github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/storage"
+	"github.com/scosta/goal-app/internal/tasks"
 )
 
 func main() {
@@ -43,6 +53,12 @@ func main() {
 	}
 	defer fsClient.Close()
 
+	// Metrics registry for this process; threaded into every collector and
+	// middleware instead of binding to prometheus.DefaultRegisterer, so
+	// tests (and any future second instance) don't share global state.
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+
 	// Initialize PubSub client (optional for development)
 	var publisher *apppubsub.Publisher
 	if emulatorHost != "" {
@@ -59,7 +75,23 @@ func main() {
 		} else {
 			defer pubsubClient.Close()
 			// Initialize publisher
-			publisher = apppubsub.NewPublisher(ctx, pubsubClient, "goal-events")
+			publisher = apppubsub.NewPublisher(ctx, pubsubClient, "goal-events", m)
+		}
+	}
+
+	// Initialize object storage (optional for development)
+	var store storage.Store
+	if os.Getenv("STORAGE_ENDPOINT") == "" {
+		store = storage.NoopStore{}
+		log.Println("Using no-op object store for development")
+	} else {
+		minioStore, err := storage.NewMinioStoreFromEnv()
+		if err != nil {
+			log.Printf("Warning: Failed to create object store: %v", err)
+			log.Println("Using no-op object store instead")
+			store = storage.NoopStore{}
+		} else {
+			store = minioStore
 		}
 	}
 
@@ -69,6 +101,7 @@ func main() {
 	// Add middleware
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(observability.Middleware(observability.NewConfigFromEnv(publisher, m)))
 
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
@@ -84,6 +117,18 @@ func main() {
 		c.Next()
 	})
 
+	// Initialize async task enqueuer (optional for development: recompute
+	// endpoints 500 if Redis isn't reachable rather than blocking startup).
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+	taskStatus := &tasks.StatusStore{Fs: fsClient, Coll: "tasks"}
+	taskEnqueuer := &tasks.Enqueuer{
+		Client: asynq.NewClient(asynq.RedisClientOpt{Addr: redisAddr}),
+		Status: taskStatus,
+	}
+
 	// Health endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -92,51 +137,98 @@ func main() {
 		})
 	})
 
+	// Prometheus scrape endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(reg, promhttp.HandlerOpts{})))
+
 	// Initialize handlers
+	notifier, err := notify.NewNotifierFromEnv()
+	if err != nil {
+		log.Printf("Warning: Failed to build notifier: %v", err)
+		log.Println("Using no-op notifier instead")
+		notifier = notify.NoopNotifier{}
+	}
+
+	idempotencyColl := "idempotency_keys"
+
 	goalHandler := &handlers.GoalHandler{
-		Fs:   fsClient,
-		Pub:  publisher,
-		Coll: "goals",
+		Fs:          fsClient,
+		Pub:         publisher,
+		Tasks:       taskEnqueuer,
+		Coll:        "goals",
+		Clock:       clock.RealClock{},
+		Notifier:    notifier,
+		Idempotency: &handlers.IdempotencyStore{Fs: fsClient, Coll: idempotencyColl},
+		Metrics:     m,
 	}
 
 	progressHandler := &handlers.ProgressHandler{
-		Fs:   fsClient,
-		Pub:  publisher,
-		Coll: "progress",
+		Fs:          fsClient,
+		Pub:         publisher,
+		Store:       store,
+		Tasks:       taskEnqueuer,
+		Coll:        "progress",
+		Clock:       clock.RealClock{},
+		Idempotency: &handlers.IdempotencyStore{Fs: fsClient, Coll: idempotencyColl},
+		Metrics:     m,
 	}
 
 	summaryHandler := &handlers.SummaryHandler{
 		Fs:           fsClient,
 		ProgressColl: "progress",
 		GoalsColl:    "goals",
+		Clock:        clock.RealClock{},
+		Metrics:      m,
 	}
 
-	// Set up routes with handlers
+	taskHandler := &handlers.TaskHandler{Tasks: taskEnqueuer, Clock: clock.RealClock{}}
+
+	// Set up routes with handlers. Every /api route requires a valid
+	// bearer token; /health stays public above.
 	api := router.Group("/api")
+	api.Use(auth.Middleware(auth.NewConfigFromEnv()))
 	{
 		// Goals routes
 		goals := api.Group("/goals")
 		{
-			goals.POST("", goalHandler.CreateGoal)
-			goals.GET("", goalHandler.ListGoals)
+			goals.POST("", m.Middleware(), goalHandler.CreateGoal)
+			goals.GET("", m.Middleware(), goalHandler.ListGoals)
+			goals.PUT("/:goalId", goalHandler.UpdateGoal)
+			goals.POST("/:goalId/recompute", auth.RequireRole("admin"), goalHandler.RecomputeGoalStats)
 		}
 
 		// Progress routes
 		progress := api.Group("/progress")
 		{
-			progress.POST("", progressHandler.RecordProgress)
-			progress.GET("", progressHandler.GetProgress)
-			progress.GET("/:goalId", progressHandler.GetProgressForGoal)
+			progress.POST("", m.Middleware(), progressHandler.RecordProgress)
+			progress.GET("", m.Middleware(), progressHandler.GetProgress)
+			progress.GET("/export", m.Middleware(), progressHandler.ExportProgress)
+			progress.POST("/recompute", auth.RequireRole("admin"), progressHandler.RecomputeMonth)
+			// Gin registers one route tree per HTTP method, but every
+			// wildcard at a given depth within the GET tree must share a
+			// name - so GetProgressForGoal, GetAttachment, and
+			// GetProgressForMonth all bind the first segment as
+			// :progressId even where it's semantically a goalId or year.
+			progress.GET("/:progressId", progressHandler.GetProgressForGoal)
 			progress.PUT("/:progressId", progressHandler.UpdateProgress)
 			progress.DELETE("/:progressId", progressHandler.DeleteProgress)
+			progress.POST("/:progressId/attachments", progressHandler.UploadAttachment)
+			progress.GET("/:progressId/attachments/:key", progressHandler.GetAttachment)
+			progress.DELETE("/:progressId/attachments/:key", progressHandler.DeleteAttachment)
+			// Also serves the year/month browse endpoint
+			// GET /progress/{year}/{month}; see GetProgressForGoal.
+			progress.GET("/:progressId/:month", progressHandler.GetProgressForMonth)
 		}
 
 		// Summary routes
 		summary := api.Group("/summary")
 		{
-			summary.GET("/monthly", summaryHandler.GetMonthlySummary)
-			summary.GET("/yearly", summaryHandler.GetYearlySummary)
+			summary.GET("/monthly", m.Middleware(), summaryHandler.GetMonthlySummary)
+			summary.GET("/yearly", m.Middleware(), summaryHandler.GetYearlySummary)
+			summary.GET("/yearly/export", m.Middleware(), summaryHandler.ExportYearlySummary)
 		}
+
+		// Recompute job status
+		api.GET("/tasks/:taskId", taskHandler.GetTaskStatus)
 	}
 
 	log.Println("Server starting on :8080")
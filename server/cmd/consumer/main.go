@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	"github.com/joho/godotenv"
+
+	appconsumer "github.com/scosta/goal-app/internal/consumer"
+	"github.com/scosta/goal-app/internal/notify"
+)
+
+func main() {
+	fmt.Println("Starting Goal App consumer...")
+
+	// Load environment variables from .env file in project root
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("No .env file found in project root, using system environment variables")
+	}
+
+	ctx := context.Background()
+	projectID := os.Getenv("FIRESTORE_PROJECT_ID")
+	if projectID == "" {
+		projectID = "test-project" // Default for development
+	}
+
+	fsClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatal("Failed to create Firestore client:", err)
+	}
+	defer fsClient.Close()
+
+	pubsubClient, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatal("Failed to create PubSub client:", err)
+	}
+	defer pubsubClient.Close()
+
+	subID := os.Getenv("GOAL_EVENTS_SUBSCRIPTION")
+	if subID == "" {
+		subID = "goal-events-consumer"
+	}
+	source := &appconsumer.PubsubSource{Sub: pubsubClient.Subscription(subID)}
+
+	statsHandler := &appconsumer.GoalStatsHandler{
+		Fs:              fsClient,
+		GoalsColl:       "goals",
+		ProgressColl:    "progress",
+		StatsColl:       "goal_stats",
+		IdempotencyColl: "consumer_idempotency",
+	}
+
+	notifier, err := notify.NewNotifierFromEnv()
+	if err != nil {
+		log.Fatal("Failed to build notifier:", err)
+	}
+	reminderHandler := &appconsumer.ReminderHandler{
+		Fs:        fsClient,
+		GoalsColl: "goals",
+		Notifier:  notifier,
+	}
+
+	sub := appconsumer.NewSubscriber(source)
+	sub.On("progress.recorded", statsHandler.HandleProgressRecorded)
+	sub.On("progress.updated", statsHandler.HandleProgressRecorded)
+	sub.On("goal.created", reminderHandler.HandleGoalCreated)
+	sub.On("progress.missed", reminderHandler.HandleProgressMissed)
+
+	log.Printf("Consumer listening on subscription %s", subID)
+	if err := sub.Run(ctx); err != nil {
+		log.Fatal("Consumer stopped:", err)
+	}
+}
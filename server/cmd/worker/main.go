@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	"github.com/hibiken/asynq"
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scosta/goal-app/internal/metrics"
+	apppubsub "github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/tasks"
+)
+
+func main() {
+	fmt.Println("Starting Goal App recompute worker...")
+
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("No .env file found in project root, using system environment variables")
+	}
+
+	ctx := context.Background()
+	projectID := os.Getenv("FIRESTORE_PROJECT_ID")
+	if projectID == "" {
+		projectID = "test-project" // Default for development
+	}
+
+	fsClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Fatal("Failed to create Firestore client:", err)
+	}
+	defer fsClient.Close()
+
+	m := metrics.New(prometheus.NewRegistry())
+
+	// Initialize PubSub client (optional for development), same fallback
+	// pattern as cmd/api.
+	var publisher *apppubsub.Publisher
+	emulatorHost := os.Getenv("FIRESTORE_EMULATOR_HOST")
+	if emulatorHost != "" {
+		publisher = &apppubsub.Publisher{}
+		log.Println("Using mock publisher for development")
+	} else {
+		pubsubClient, err := pubsub.NewClient(ctx, projectID)
+		if err != nil {
+			log.Printf("Warning: Failed to create PubSub client: %v", err)
+			log.Println("Using mock publisher instead")
+			publisher = &apppubsub.Publisher{}
+		} else {
+			defer pubsubClient.Close()
+			publisher = apppubsub.NewPublisher(ctx, pubsubClient, "goal-events", m)
+		}
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+
+	status := &tasks.StatusStore{Fs: fsClient, Coll: "tasks"}
+	worker := &tasks.Worker{
+		Fs:           fsClient,
+		GoalsColl:    "goals",
+		ProgressColl: "progress",
+		StatsColl:    "goal_stats",
+		Status:       status,
+		Pub:          publisher,
+	}
+
+	redisOpt := asynq.RedisClientOpt{Addr: redisAddr}
+
+	scheduler := asynq.NewScheduler(redisOpt, nil)
+	checkMissedProgressTask, err := tasks.NewCheckMissedProgressTask()
+	if err != nil {
+		log.Fatal("Failed to build check-missed-progress task:", err)
+	}
+	if _, err := scheduler.Register("0 6 * * *", checkMissedProgressTask); err != nil {
+		log.Fatal("Failed to register check-missed-progress schedule:", err)
+	}
+	go func() {
+		if err := scheduler.Run(); err != nil {
+			log.Fatal("Scheduler failed to start:", err)
+		}
+	}()
+
+	srv := asynq.NewServer(redisOpt, asynq.Config{Concurrency: 10})
+
+	log.Println("Worker listening for recompute tasks on", redisAddr)
+	if err := srv.Run(worker.Mux()); err != nil {
+		log.Fatal("Worker failed to start:", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package auth validates bearer JWTs on incoming requests and exposes the
+// caller's identity to handlers via the Gin context (uid, email, roles).
+package auth
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the subset of the token's claims handlers care about.
+type Claims struct {
+	jwt.RegisteredClaims
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+// Config controls how Middleware verifies tokens. NewConfigFromEnv builds
+// this from JWT_SECRET (local HS256) and/or JWT_JWKS_URL (prod RS256).
+type Config struct {
+	// HMACSecret, if set, validates HS256 tokens signed with this secret.
+	// Intended for local development only.
+	HMACSecret []byte
+	// JWKSURL, if set, validates RS256 tokens against keys fetched from
+	// this JWKS endpoint.
+	JWKSURL string
+}
+
+// NewConfigFromEnv reads JWT_SECRET and JWT_JWKS_URL.
+func NewConfigFromEnv() Config {
+	cfg := Config{JWKSURL: os.Getenv("JWT_JWKS_URL")}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.HMACSecret = []byte(secret)
+	}
+	return cfg
+}
+
+// Middleware validates the Authorization: Bearer <token> header and sets
+// "uid", "email", and "roles" on the context. Requests without a valid
+// token are rejected with 401 before reaching the handler.
+func Middleware(cfg Config) gin.HandlerFunc {
+	keyfunc := cfg.keyfunc()
+	return func(c *gin.Context) {
+		tokenString := bearerToken(c.GetHeader("Authorization"))
+		if tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, keyfunc)
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		c.Set("uid", claims.Subject)
+		c.Set("email", claims.Email)
+		c.Set("roles", claims.Roles)
+		c.Next()
+	}
+}
+
+// RequireRole rejects the request with 403 unless the token's roles (set by
+// Middleware) include role. Used to gate admin-only endpoints like the
+// recompute/rejudge handlers.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, _ := c.Get("roles")
+		if rs, ok := roles.([]string); ok {
+			for _, r := range rs {
+				if r == role {
+					c.Next()
+					return
+				}
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "requires role " + role})
+	}
+}
+
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
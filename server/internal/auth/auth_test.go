@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signHS256(t *testing.T, secret []byte, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(secret)
+	require.NoError(t, err)
+	return signed
+}
+
+func newTestClaims(uid string, roles []string) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uid,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Email: uid + "@example.com",
+		Roles: roles,
+	}
+}
+
+func performRequest(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-secret")
+
+	tests := []struct {
+		name         string
+		token        func(t *testing.T) string
+		expectStatus int
+		expectUid    string
+	}{
+		{
+			name: "valid signed token",
+			token: func(t *testing.T) string {
+				return signHS256(t, secret, newTestClaims("user-1", []string{"member"}))
+			},
+			expectStatus: http.StatusOK,
+			expectUid:    "user-1",
+		},
+		{
+			name: "tampered signature",
+			token: func(t *testing.T) string {
+				signed := signHS256(t, secret, newTestClaims("user-1", nil))
+				return signed[:len(signed)-1] + "x"
+			},
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "signed with the wrong secret",
+			token: func(t *testing.T) string {
+				return signHS256(t, []byte("wrong-secret"), newTestClaims("user-1", nil))
+			},
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "expired token",
+			token: func(t *testing.T) string {
+				claims := newTestClaims("user-1", nil)
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return signHS256(t, secret, claims)
+			},
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "missing token",
+			token:        func(t *testing.T) string { return "" },
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Middleware(Config{HMACSecret: secret}))
+			router.GET("/protected", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"uid": c.GetString("uid")})
+			})
+
+			w := performRequest(router, "/protected", tt.token(t))
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectUid != "" {
+				assert.Contains(t, w.Body.String(), tt.expectUid)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-secret")
+
+	tests := []struct {
+		name         string
+		roles        []string
+		expectStatus int
+	}{
+		{name: "has required role", roles: []string{"member", "admin"}, expectStatus: http.StatusOK},
+		{name: "missing required role", roles: []string{"member"}, expectStatus: http.StatusForbidden},
+		{name: "no roles", roles: nil, expectStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Middleware(Config{HMACSecret: secret}))
+			router.GET("/admin", RequireRole("admin"), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			})
+
+			token := signHS256(t, secret, newTestClaims("user-1", tt.roles))
+			w := performRequest(router, "/admin", token)
+			assert.Equal(t, tt.expectStatus, w.Code)
+		})
+	}
+}
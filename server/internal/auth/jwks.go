@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksRefreshInterval is how long a fetched key set is served from cache
+// before publicKey refetches it. A kid missing from the cache triggers a
+// refetch immediately, however recently the set was last fetched.
+const jwksRefreshInterval = 5 * time.Minute
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jwksCache fetches and caches a JWKS endpoint's RSA public keys, keyed by
+// kid, so key rotation is picked up without a restart.
+type jwksCache struct {
+	url string
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// keyfunc returns the jwt.Keyfunc Middleware uses to resolve a token's
+// signing key: a static HMAC secret for HS256, or a cached JWKS lookup for
+// RS256. If both are configured, the token's algorithm decides which path
+// is taken.
+func (cfg Config) keyfunc() jwt.Keyfunc {
+	var cache *jwksCache
+	if cfg.JWKSURL != "" {
+		cache = &jwksCache{url: cfg.JWKSURL, keys: map[string]*rsa.PublicKey{}}
+	}
+
+	return func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if cfg.HMACSecret == nil {
+				return nil, errors.New("HS256 tokens are not accepted: JWT_SECRET is not configured")
+			}
+			return cfg.HMACSecret, nil
+		case *jwt.SigningMethodRSA:
+			if cache == nil {
+				return nil, errors.New("RS256 tokens are not accepted: JWT_JWKS_URL is not configured")
+			}
+			kid, _ := token.Header["kid"].(string)
+			return cache.publicKey(kid)
+		default:
+			return nil, fmt.Errorf("unsupported signing method: %v", token.Header["alg"])
+		}
+	}
+}
+
+// publicKey returns the RSA public key for kid, refreshing the cached key
+// set if kid hasn't been seen yet (key rotation) or the cache has expired.
+func (c *jwksCache) publicKey(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[kid]; ok && time.Since(c.fetched) < jwksRefreshInterval {
+		return key, nil
+	}
+	if err := c.refresh(); err != nil {
+		return nil, err
+	}
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no matching key for kid %q", kid)
+	}
+	return key, nil
+}
+
+// refresh must be called with c.mu held.
+func (c *jwksCache) refresh() error {
+	resp, err := http.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := decodeRSAPublicKey(k.N, k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	c.keys = keys
+	c.fetched = time.Now()
+	return nil
+}
+
+// decodeRSAPublicKey builds an *rsa.PublicKey from a JWK's base64url-encoded
+// modulus (n) and exponent (e).
+func decodeRSAPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
@@ -0,0 +1,55 @@
+// Package clock abstracts time.Now so handlers can be tested
+// deterministically instead of racing against the wall clock near day,
+// month, or year boundaries.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time. RealClock is used in production;
+// FakeClock lets tests pin or advance time explicitly.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock delegates to time.Now.
+type RealClock struct{}
+
+// Now returns the current wall-clock time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock with a fixed, manually-advanced time, for tests.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock starting at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set pins the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d (use a negative d to rewind).
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
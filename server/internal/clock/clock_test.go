@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClockSetAndAdvance(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(24 * time.Hour)
+	assert.Equal(t, start.AddDate(0, 0, 1), c.Now())
+
+	later := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Set(later)
+	assert.Equal(t, later, c.Now())
+}
+
+func TestRealClockReturnsCurrentTime(t *testing.T) {
+	before := time.Now()
+	got := RealClock{}.Now()
+	after := time.Now()
+	assert.False(t, got.Before(before))
+	assert.False(t, got.After(after))
+}
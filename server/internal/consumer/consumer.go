@@ -0,0 +1,78 @@
+// Package consumer dispatches goal-events messages to per-event-type
+// handlers, mirroring the woj-server consumer module: a dispatcher plus
+// small handler functions rather than one monolithic switch.
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// Event is the envelope published onto the goal-events topic by
+// pubsub.Publisher: {"type": "...", "payload": {...}}. MessageID is not part
+// of that envelope - it's the underlying transport's message ID, stamped on
+// by Run from whatever MessageSource.Receive handed it, so handlers have a
+// value that actually changes between redeliveries and distinct updates to
+// use for dedupe (unlike anything in Payload itself).
+type Event struct {
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	MessageID string          `json:"-"`
+}
+
+// EventHandler processes a single decoded event. Delivery is at-least-once,
+// so handlers must be idempotent.
+type EventHandler func(ctx context.Context, event Event) error
+
+// MessageSource abstracts pulling messages so tests can substitute an
+// in-memory implementation instead of a real Pub/Sub subscription. id is the
+// transport's message ID, stable across redelivery of the same message.
+type MessageSource interface {
+	Receive(ctx context.Context, handle func(ctx context.Context, id string, data []byte, ack, nack func())) error
+}
+
+// Subscriber dispatches events pulled from a MessageSource to handlers
+// registered by event type.
+type Subscriber struct {
+	source   MessageSource
+	handlers map[string]EventHandler
+}
+
+// NewSubscriber creates a Subscriber pulling from source.
+func NewSubscriber(source MessageSource) *Subscriber {
+	return &Subscriber{source: source, handlers: make(map[string]EventHandler)}
+}
+
+// On registers handler for the given event type (e.g. "progress.recorded").
+func (s *Subscriber) On(eventType string, handler EventHandler) {
+	s.handlers[eventType] = handler
+}
+
+// Run pulls messages until ctx is canceled or the source returns, dispatching
+// each message to its registered handler. Unrecognized event types and
+// malformed payloads are acked and dropped rather than retried forever.
+func (s *Subscriber) Run(ctx context.Context) error {
+	return s.source.Receive(ctx, func(ctx context.Context, id string, data []byte, ack, nack func()) {
+		var evt Event
+		if err := json.Unmarshal(data, &evt); err != nil {
+			log.Printf("consumer: dropping malformed event: %v", err)
+			ack()
+			return
+		}
+		evt.MessageID = id
+
+		handler, ok := s.handlers[evt.Type]
+		if !ok {
+			ack()
+			return
+		}
+
+		if err := handler(ctx, evt); err != nil {
+			log.Printf("consumer: handler for %q failed: %v", evt.Type, err)
+			nack()
+			return
+		}
+		ack()
+	})
+}
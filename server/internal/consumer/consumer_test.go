@@ -0,0 +1,57 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriberDispatchesByEventType(t *testing.T) {
+	source := &MockSource{}
+	sub := NewSubscriber(source)
+
+	var received []string
+	sub.On("progress.recorded", func(ctx context.Context, evt Event) error {
+		received = append(received, evt.Type)
+		return nil
+	})
+
+	recorded, err := json.Marshal(map[string]interface{}{
+		"type":    "progress.recorded",
+		"payload": map[string]interface{}{"id": "p1"},
+	})
+	require.NoError(t, err)
+	source.Publish(recorded)
+
+	// An event type with no registered handler should be skipped, not error.
+	unhandled, err := json.Marshal(map[string]interface{}{
+		"type":    "goal.created",
+		"payload": map[string]interface{}{"id": "g1"},
+	})
+	require.NoError(t, err)
+	source.Publish(unhandled)
+
+	err = sub.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"progress.recorded"}, received)
+}
+
+func TestSubscriberDropsMalformedEvents(t *testing.T) {
+	source := &MockSource{}
+	sub := NewSubscriber(source)
+
+	called := false
+	sub.On("progress.recorded", func(ctx context.Context, evt Event) error {
+		called = true
+		return nil
+	})
+
+	source.Publish([]byte("not json"))
+
+	err := sub.Run(context.Background())
+	require.NoError(t, err)
+	assert.False(t, called)
+}
@@ -0,0 +1,169 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scosta/goal-app/internal/handlers"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// GoalStats is the materialized per-goal document this consumer maintains so
+// SummaryHandler can read O(1) documents instead of scanning collections.
+type GoalStats struct {
+	GoalId         string    `firestore:"goalId" json:"goalId"`
+	CurrentStreak  int       `firestore:"currentStreak" json:"currentStreak"`
+	LongestStreak  int       `firestore:"longestStreak" json:"longestStreak"`
+	LastActiveDate time.Time `firestore:"lastActiveDate" json:"lastActiveDate"`
+	MTDMinutes     int       `firestore:"mtdMinutes" json:"mtdMinutes"`
+	YTDMinutes     int       `firestore:"ytdMinutes" json:"ytdMinutes"`
+}
+
+// GoalStatsHandler recomputes TargetMet and the goal_stats/{goalId}
+// materialized view in response to progress events.
+type GoalStatsHandler struct {
+	Fs              *firestore.Client
+	GoalsColl       string
+	ProgressColl    string
+	StatsColl       string
+	IdempotencyColl string
+}
+
+// HandleProgressRecorded handles progress.recorded and progress.updated
+// events: it looks up the referenced goal, recomputes TargetMet against
+// TargetMinutesPerDay, and refreshes the goal's materialized stats doc.
+func (h *GoalStatsHandler) HandleProgressRecorded(ctx context.Context, evt Event) error {
+	var payload openapi.Progress
+	if err := json.Unmarshal(evt.Payload, &payload); err != nil {
+		return fmt.Errorf("decode progress payload: %w", err)
+	}
+
+	key := idempotencyKey(payload.Id, evt.MessageID)
+	seen, err := h.alreadyProcessed(ctx, key)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return nil
+	}
+
+	goalDoc, err := h.Fs.Collection(h.GoalsColl).Doc(payload.GoalId).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil // goal was deleted out from under us; nothing to recompute
+	}
+	if err != nil {
+		return fmt.Errorf("load goal %s: %w", payload.GoalId, err)
+	}
+
+	var goal openapi.Goal
+	if err := goalDoc.DataTo(&goal); err != nil {
+		return fmt.Errorf("decode goal %s: %w", payload.GoalId, err)
+	}
+
+	targetMet := payload.MinutesSpent >= goal.TargetMinutesPerDay
+	if targetMet != payload.TargetMet {
+		if _, err := h.Fs.Collection(h.ProgressColl).Doc(payload.Id).Update(ctx, []firestore.Update{
+			{Path: "targetMet", Value: targetMet},
+		}); err != nil {
+			return fmt.Errorf("update targetMet for %s: %w", payload.Id, err)
+		}
+	}
+
+	if err := h.refreshGoalStats(ctx, payload.GoalId); err != nil {
+		return err
+	}
+
+	return h.markProcessed(ctx, key)
+}
+
+// refreshGoalStats recomputes streaks and month-to-date/year-to-date minutes
+// for a goal from its full progress history and writes the result to
+// goal_stats/{goalId}.
+func (h *GoalStatsHandler) refreshGoalStats(ctx context.Context, goalId string) error {
+	stats, err := ComputeGoalStats(ctx, h.Fs, h.ProgressColl, goalId)
+	if err != nil {
+		return err
+	}
+	_, err = h.Fs.Collection(h.StatsColl).Doc(goalId).Set(ctx, stats)
+	return err
+}
+
+// ComputeGoalStats recomputes a goal's streaks and month-to-date/year-to-date
+// minutes from its full progress history. Exported so internal/tasks' worker
+// can write the exact same goal_stats/{goalId} shape on an on-demand
+// recompute that refreshGoalStats writes in response to progress events -
+// otherwise the two paths drift apart and can stomp each other's output.
+func ComputeGoalStats(ctx context.Context, fs *firestore.Client, progressColl, goalId string) (GoalStats, error) {
+	iter := fs.Collection(progressColl).Where("goalId", "==", goalId).Documents(ctx)
+	var entries []openapi.Progress
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			break
+		}
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err == nil {
+			entries = append(entries, p)
+		}
+	}
+
+	current, longest := handlers.CalculateStreak(entries, handlers.StreakOptions{})
+
+	now := time.Now().UTC()
+	var mtdMinutes, ytdMinutes int
+	var lastActive time.Time
+	for _, p := range entries {
+		d := p.Date.Time
+		if d.Year() == now.Year() {
+			ytdMinutes += p.MinutesSpent
+			if d.Month() == now.Month() {
+				mtdMinutes += p.MinutesSpent
+			}
+		}
+		if d.After(lastActive) {
+			lastActive = d
+		}
+	}
+
+	return GoalStats{
+		GoalId:         goalId,
+		CurrentStreak:  current,
+		LongestStreak:  longest,
+		LastActiveDate: lastActive,
+		MTDMinutes:     mtdMinutes,
+		YTDMinutes:     ytdMinutes,
+	}, nil
+}
+
+func (h *GoalStatsHandler) alreadyProcessed(ctx context.Context, key string) (bool, error) {
+	_, err := h.Fs.Collection(h.IdempotencyColl).Doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *GoalStatsHandler) markProcessed(ctx context.Context, key string) error {
+	_, err := h.Fs.Collection(h.IdempotencyColl).Doc(key).Set(ctx, map[string]interface{}{
+		"processedAt": time.Now().UTC(),
+	})
+	return err
+}
+
+// idempotencyKey derives a dedupe key from the progress entry's ID and the
+// Pub/Sub message ID. The message ID (not CreatedAt, which is set once and
+// never changes) is what actually differs between a redelivery of the same
+// event - which should dedupe - and a later, distinct progress.updated event
+// for the same entry, which must still recompute stats.
+func idempotencyKey(progressId, messageID string) string {
+	return fmt.Sprintf("%s-%s", progressId, messageID)
+}
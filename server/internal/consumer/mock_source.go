@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+)
+
+// MockSource is an in-memory MessageSource for tests, mirroring the mock
+// pubsub.Publisher used elsewhere in this codebase.
+type MockSource struct {
+	messages [][]byte
+}
+
+// Publish enqueues a raw event payload to be delivered on the next Receive.
+func (m *MockSource) Publish(data []byte) {
+	m.messages = append(m.messages, data)
+}
+
+// Receive delivers every enqueued message once, in order, then returns nil.
+// Each message gets a distinct synthetic ID (mock-0, mock-1, ...), mirroring
+// how real Pub/Sub gives every published message its own message ID.
+func (m *MockSource) Receive(ctx context.Context, handle func(ctx context.Context, id string, data []byte, ack, nack func())) error {
+	for i, data := range m.messages {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		handle(ctx, fmt.Sprintf("mock-%d", i), data, func() {}, func() {})
+	}
+	return nil
+}
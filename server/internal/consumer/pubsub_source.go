@@ -0,0 +1,18 @@
+package consumer
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// PubsubSource adapts a *pubsub.Subscription to MessageSource.
+type PubsubSource struct {
+	Sub *pubsub.Subscription
+}
+
+func (s *PubsubSource) Receive(ctx context.Context, handle func(ctx context.Context, id string, data []byte, ack, nack func())) error {
+	return s.Sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		handle(ctx, msg.ID, msg.Data, msg.Ack, msg.Nack)
+	})
+}
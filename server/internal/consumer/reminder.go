@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/notify"
+)
+
+// ProgressMissedPayload is the progress.missed event payload published by
+// the worker's missed-progress check: a goal that expected activity on Date
+// and didn't get any.
+type ProgressMissedPayload struct {
+	GoalId string    `json:"goalId"`
+	Date   time.Time `json:"date"`
+}
+
+// ReminderHandler sends accountability emails in response to goal.created
+// and progress.missed events.
+type ReminderHandler struct {
+	Fs        *firestore.Client
+	GoalsColl string
+	Notifier  notify.Notifier
+}
+
+// HandleGoalCreated welcomes a new goal with a confirmation email.
+func (h *ReminderHandler) HandleGoalCreated(ctx context.Context, evt Event) error {
+	var goal openapi.Goal
+	if err := json.Unmarshal(evt.Payload, &goal); err != nil {
+		return fmt.Errorf("decode goal payload: %w", err)
+	}
+	if goal.UserEmail == "" {
+		return nil // no address on file; nothing to send
+	}
+
+	return h.Notifier.Send(ctx, notify.Message{
+		To:       goal.UserEmail,
+		Subject:  fmt.Sprintf("New goal: %s", goal.Title),
+		TextBody: fmt.Sprintf("You're now tracking %q. Good luck!", goal.Title),
+	})
+}
+
+// HandleProgressMissed looks up the referenced goal and nudges the user
+// that the scheduled activity for the event's Date hasn't been logged.
+func (h *ReminderHandler) HandleProgressMissed(ctx context.Context, evt Event) error {
+	var payload ProgressMissedPayload
+	if err := json.Unmarshal(evt.Payload, &payload); err != nil {
+		return fmt.Errorf("decode progress.missed payload: %w", err)
+	}
+
+	goalDoc, err := h.Fs.Collection(h.GoalsColl).Doc(payload.GoalId).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil // goal was deleted out from under us; nothing to send
+	}
+	if err != nil {
+		return fmt.Errorf("load goal %s: %w", payload.GoalId, err)
+	}
+
+	var goal openapi.Goal
+	if err := goalDoc.DataTo(&goal); err != nil {
+		return fmt.Errorf("decode goal %s: %w", payload.GoalId, err)
+	}
+	if goal.UserEmail == "" {
+		return nil
+	}
+
+	return h.Notifier.Send(ctx, notify.Message{
+		To:       goal.UserEmail,
+		Subject:  fmt.Sprintf("Don't break your streak: %s", goal.Title),
+		TextBody: fmt.Sprintf("You haven't logged progress on %q for %s yet.", goal.Title, payload.Date.Format("Jan 2, 2006")),
+	})
+}
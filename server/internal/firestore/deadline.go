@@ -0,0 +1,15 @@
+package firestore
+
+import (
+	"context"
+	"time"
+)
+
+// WithOpDeadline derives a child context bounded by timeout from parent, so
+// a single Firestore operation can't outlive the request it serves. It's
+// the same parent-context-plus-idempotent-cancel shape used for setting a
+// deadline on a net.Conn: the returned cancel is safe to call more than
+// once (e.g. once on an early-return path and again from a handler defer).
+func WithOpDeadline(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, timeout)
+}
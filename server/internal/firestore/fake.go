@@ -0,0 +1,48 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// FakeDocRef is an in-memory DocRef for tests that need to control timing
+// or errors without a real Firestore connection - e.g. simulating a slow
+// Get to exercise Do/WithOpDeadline's retry-then-504 path.
+type FakeDocRef struct {
+	GetFunc func(ctx context.Context) (*firestore.DocumentSnapshot, error)
+	SetFunc func(ctx context.Context, data interface{}, opts ...firestore.SetOption) (*firestore.WriteResult, error)
+
+	// Delay, if set, is how long Get waits before calling GetFunc; it
+	// returns ctx.Err() instead if ctx is done first, the same way a real
+	// Firestore call would abandon a request whose deadline already fired.
+	Delay time.Duration
+}
+
+func (f *FakeDocRef) Get(ctx context.Context) (*firestore.DocumentSnapshot, error) {
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.GetFunc != nil {
+		return f.GetFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (f *FakeDocRef) Set(ctx context.Context, data interface{}, opts ...firestore.SetOption) (*firestore.WriteResult, error) {
+	if f.SetFunc != nil {
+		return f.SetFunc(ctx, data, opts...)
+	}
+	return nil, nil
+}
+
+func (f *FakeDocRef) Delete(ctx context.Context, opts ...firestore.Precondition) (*firestore.WriteResult, error) {
+	return nil, nil
+}
+
+var _ DocRef = (*FakeDocRef)(nil)
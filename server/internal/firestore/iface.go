@@ -0,0 +1,20 @@
+package firestore
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+)
+
+// DocRef is the subset of *firestore.DocumentRef handlers call directly
+// (get-then-maybe-write on a single document). It's narrow enough that
+// *firestore.DocumentRef already satisfies it, so production code needs no
+// adapter, while tests can substitute a FakeDocRef to control timing and
+// errors without an emulator.
+type DocRef interface {
+	Get(ctx context.Context) (*firestore.DocumentSnapshot, error)
+	Set(ctx context.Context, data interface{}, opts ...firestore.SetOption) (*firestore.WriteResult, error)
+	Delete(ctx context.Context, opts ...firestore.Precondition) (*firestore.WriteResult, error)
+}
+
+var _ DocRef = (*firestore.DocumentRef)(nil)
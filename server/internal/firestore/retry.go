@@ -0,0 +1,93 @@
+package firestore
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Budget bounds how many times Do retries a transient Firestore error and
+// how long it waits between attempts.
+type Budget struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultBudget is a reasonable budget for an interactive request handler:
+// up to 3 attempts, with a 50ms base delay that doubles per retry (capped
+// at 500ms) and up to half of it taken off as jitter.
+func DefaultBudget() Budget {
+	return Budget{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+}
+
+// ErrBudgetExhausted is returned by Do when op kept failing with a
+// retryable error until the budget ran out. Handlers should translate this
+// into a 504.
+var ErrBudgetExhausted = errors.New("firestore: retry budget exhausted")
+
+// Do calls op, retrying with exponential backoff and jitter while op
+// returns a retryable error (codes.Unavailable or codes.DeadlineExceeded)
+// and the budget allows another attempt. A non-retryable error is returned
+// immediately; ctx firing - whether op was mid-flight or Do was waiting out
+// the backoff between attempts - is reported as ErrBudgetExhausted rather
+// than the raw ctx.Err(), the same as a retry budget that ran out on its
+// own, so callers only need one errors.Is check.
+func Do(ctx context.Context, budget Budget, op func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < budget.MaxAttempts; attempt++ {
+		err = op(ctx)
+		if err == nil {
+			return nil
+		}
+		// status.Code on a bare context.DeadlineExceeded/Canceled (what op
+		// returns once ctx - usually one WithOpDeadline set up - fires) is
+		// codes.Unknown, not codes.DeadlineExceeded, so isRetryable alone
+		// would treat it as non-retryable and return it as-is. Since
+		// there's no point retrying against a context that's already done,
+		// report it the same way as a real exhausted retry budget.
+		if ctx.Err() != nil {
+			return ErrBudgetExhausted
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		if attempt == budget.MaxAttempts-1 {
+			break
+		}
+
+		select {
+		case <-time.After(backoff(budget, attempt)):
+		case <-ctx.Done():
+			// Same reasoning as the ctx.Err() check above op: a deadline
+			// firing while we're waiting between attempts is not worth
+			// distinguishing from the budget running out.
+			return ErrBudgetExhausted
+		}
+	}
+	return ErrBudgetExhausted
+}
+
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff computes an exponential delay for attempt capped at MaxDelay,
+// with up to 50% jitter so concurrent retries don't all land together.
+func backoff(budget Budget, attempt int) time.Duration {
+	delay := budget.BaseDelay << attempt
+	if delay <= 0 || delay > budget.MaxDelay {
+		delay = budget.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	return delay/2 + jitter/2
+}
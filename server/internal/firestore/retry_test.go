@@ -0,0 +1,99 @@
+package firestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithOpDeadlineCancelIsIdempotent(t *testing.T) {
+	ctx, cancel := WithOpDeadline(context.Background(), time.Second)
+	cancel()
+	cancel() // must not panic
+
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestDoFailsPromptlyWhenOperationExceedsDeadline(t *testing.T) {
+	ctx, cancel := WithOpDeadline(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	doc := &FakeDocRef{Delay: 500 * time.Millisecond}
+	budget := Budget{MaxAttempts: 2, BaseDelay: 5 * time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	start := time.Now()
+	err := Do(ctx, budget, func(ctx context.Context) error {
+		_, err := doc.Get(ctx)
+		return err
+	})
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBudgetExhausted, "a context deadline firing mid-op should report the same way as a retry budget running out")
+	assert.Less(t, elapsed, 100*time.Millisecond, "Do should give up once the deadline fires rather than waiting out the fake's full delay")
+}
+
+func TestDoReportsBudgetExhaustedWhenDeadlineFiresDuringBackoff(t *testing.T) {
+	ctx, cancel := WithOpDeadline(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// BaseDelay far outlasts the deadline, so op (which fails instantly)
+	// isn't what's slow here - it's the sleep between attempt 1 and 2 that
+	// the deadline should interrupt.
+	budget := Budget{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+
+	attempts := 0
+	start := time.Now()
+	err := Do(ctx, budget, func(ctx context.Context) error {
+		attempts++
+		return status.Error(codes.Unavailable, "down")
+	})
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBudgetExhausted, "a deadline firing during the backoff wait should report the same way as a retry budget running out")
+	assert.Equal(t, 1, attempts, "Do should give up waiting once the deadline fires rather than sleeping out the full backoff window")
+	assert.Less(t, elapsed, 100*time.Millisecond)
+}
+
+func TestDoRetriesRetryableErrorsThenSucceeds(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), Budget{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return status.Error(codes.Unavailable, "try again")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDoReturnsBudgetExhaustedAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), Budget{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		return status.Error(codes.Unavailable, "down")
+	})
+
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestDoReturnsNonRetryableErrorImmediately(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), DefaultBudget(), func(ctx context.Context) error {
+		attempts++
+		return status.Error(codes.NotFound, "nope")
+	})
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrBudgetExhausted)
+	assert.Equal(t, 1, attempts)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"fmt"
 	"testing"
 	"time"
 
@@ -47,35 +46,6 @@ func TestGroupProgressByGoal(t *testing.T) {
 	assert.Equal(t, 60, grouped["goal_2"][0].MinutesSpent)
 }
 
-func TestCalculateGoalProgress(t *testing.T) {
-	goal := openapi.Goal{
-		Id:                  "goal_1",
-		Title:               "Learn Spanish",
-		TargetMinutesPerDay: 30,
-	}
-
-	progressEntries := []openapi.Progress{
-		{MinutesSpent: 30, CreatedAt: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
-		{MinutesSpent: 45, CreatedAt: time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
-		{MinutesSpent: 25, CreatedAt: time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)},
-	}
-
-	startDate := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC)
-
-	goalProgress := calculateGoalProgress(goal, progressEntries, startDate, endDate)
-
-	assert.Equal(t, "goal_1", goalProgress.GoalId)
-	assert.Equal(t, "Learn Spanish", goalProgress.GoalTitle)
-	assert.Equal(t, 100, goalProgress.MinutesSpent) // 30 + 45 + 25
-	assert.Equal(t, 3, goalProgress.CurrentStreak)
-	assert.Equal(t, 3, goalProgress.LongestStreak)
-
-	// Success rate should be 3 days tracked out of 31 days in October
-	expectedSuccessRate := float64(3) / float64(31) * 100.0
-	assert.InDelta(t, expectedSuccessRate, goalProgress.SuccessRate, 1.0) // Allow 1% tolerance
-}
-
 func TestCalculateMonthlyAggregates(t *testing.T) {
 	progressEntries := []openapi.Progress{
 		{MinutesSpent: 30, CreatedAt: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
@@ -83,7 +53,7 @@ func TestCalculateMonthlyAggregates(t *testing.T) {
 		{MinutesSpent: 25, CreatedAt: time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)},
 	}
 
-	totalMinutes, daysTracked := calculateMonthlyAggregates(progressEntries)
+	totalMinutes, daysTracked := CalculateMonthlyAggregates(progressEntries)
 
 	assert.Equal(t, 100, totalMinutes) // 30 + 45 + 25
 	assert.Equal(t, 3, daysTracked)
@@ -225,50 +195,8 @@ func groupProgressByGoal(progressEntries []openapi.Progress) map[string][]openap
 	return grouped
 }
 
-func calculateGoalProgress(goal openapi.Goal, progressEntries []openapi.Progress, startDate, endDate time.Time) struct {
-	GoalId        string
-	GoalTitle     string
-	SuccessRate   float64
-	MinutesSpent  int
-	CurrentStreak int
-	LongestStreak int
-} {
-	totalMinutes := 0
-	for _, entry := range progressEntries {
-		totalMinutes += entry.MinutesSpent
-	}
-
-	daysInMonth := int(endDate.Sub(startDate).Hours() / 24)
-	daysTracked := len(progressEntries)
-	successRate := float64(daysTracked) / float64(daysInMonth) * 100.0
-
-	return struct {
-		GoalId        string
-		GoalTitle     string
-		SuccessRate   float64
-		MinutesSpent  int
-		CurrentStreak int
-		LongestStreak int
-	}{
-		GoalId:        goal.Id,
-		GoalTitle:     goal.Title,
-		SuccessRate:   successRate,
-		MinutesSpent:  totalMinutes,
-		CurrentStreak: daysTracked,
-		LongestStreak: daysTracked, // Simplified for now
-	}
-}
-
-func calculateMonthlyAggregates(progressEntries []openapi.Progress) (totalMinutes int, daysTracked int) {
-	totalMinutes = 0
-	daysTracked = len(progressEntries)
-
-	for _, entry := range progressEntries {
-		totalMinutes += entry.MinutesSpent
-	}
-
-	return totalMinutes, daysTracked
-}
+// CalculateMonthlyAggregates lives in summary_calc.go so internal/tasks'
+// worker can reuse it.
 
 func calculateYearlyAggregates(monthlyData []struct {
 	Month        string
@@ -323,35 +251,5 @@ func findBestAndWorstMonths(monthlyData []struct {
 	return bestMonth, worstMonth
 }
 
-func validateMonthFormat(month string) error {
-	if len(month) != 7 || month[4] != '-' {
-		return fmt.Errorf("month must be in YYYY-MM format")
-	}
-
-	year := month[:4]
-	monthNum := month[5:]
-
-	// Validate year is numeric and reasonable
-	if year < "2020" || year > "2030" {
-		return fmt.Errorf("year must be between 2020 and 2030")
-	}
-
-	// Validate month is numeric and 01-12
-	if monthNum < "01" || monthNum > "12" {
-		return fmt.Errorf("month must be between 01 and 12")
-	}
-
-	return nil
-}
-
-func validateYearFormat(year string) error {
-	if len(year) != 4 {
-		return fmt.Errorf("year must be in YYYY format")
-	}
-
-	if year < "2020" || year > "2030" {
-		return fmt.Errorf("year must be between 2020 and 2030")
-	}
-
-	return nil
-}
+// validateMonthFormat and validateYearFormat now live in validation.go so
+// the progress year/month browse routes can use them too.
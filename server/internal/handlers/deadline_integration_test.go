@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/storage"
+)
+
+// TestCreateGoalReturnsGatewayTimeoutWhenOpDeadlineFires pins OpTimeout to
+// effectively zero so the Firestore Set always loses the race against
+// WithOpDeadline's context, without needing a way to slow the emulator
+// down - this is the same path a real stuck backend would hit. It exists
+// because Do previously returned the raw context.DeadlineExceeded in this
+// case (status.Code on it is codes.Unknown, not codes.DeadlineExceeded, so
+// isRetryable never classified it as retryable) and every handler's
+// errors.Is(err, appfirestore.ErrBudgetExhausted) check missed, falling
+// through to a 500 instead of the intended 504.
+func TestCreateGoalReturnsGatewayTimeoutWhenOpDeadlineFires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fsClient, err := setupFirestoreEmulator()
+	if err != nil {
+		t.Fatalf("Failed to setup Firestore emulator: %v", err)
+	}
+	defer fsClient.Close()
+
+	goalHandler := &GoalHandler{
+		Fs:        fsClient,
+		Pub:       &pubsub.Publisher{},
+		Coll:      "goals",
+		OpTimeout: time.Nanosecond,
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("uid", "test-user-123")
+		c.Next()
+	})
+	router.POST("/goals", goalHandler.CreateGoal)
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+// TestRecordProgressReturnsGatewayTimeoutWhenOpDeadlineFires is
+// ProgressHandler's counterpart to the GoalHandler test above.
+func TestRecordProgressReturnsGatewayTimeoutWhenOpDeadlineFires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fsClient, err := setupFirestoreEmulator()
+	if err != nil {
+		t.Fatalf("Failed to setup Firestore emulator: %v", err)
+	}
+	defer fsClient.Close()
+
+	progressHandler := &ProgressHandler{
+		Fs:        fsClient,
+		Pub:       &pubsub.Publisher{},
+		Coll:      "progress",
+		OpTimeout: time.Nanosecond,
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("uid", "test-user-123")
+		c.Next()
+	})
+	router.POST("/progress", progressHandler.RecordProgress)
+
+	progressData := openapi.Progress{
+		GoalId:       "goal-1",
+		MinutesSpent: 20,
+		Date:         openapi_types.Date{Time: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	progressJSON, _ := json.Marshal(progressData)
+
+	req := httptest.NewRequest("POST", "/progress", bytes.NewBuffer(progressJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+// TestGetProgressReturnsGatewayTimeoutWhenOpDeadlineFires covers the read
+// path (fetchProgressPage), not just the writes covered above.
+func TestGetProgressReturnsGatewayTimeoutWhenOpDeadlineFires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fsClient, err := setupFirestoreEmulator()
+	if err != nil {
+		t.Fatalf("Failed to setup Firestore emulator: %v", err)
+	}
+	defer fsClient.Close()
+
+	progressHandler := &ProgressHandler{
+		Fs:        fsClient,
+		Pub:       &pubsub.Publisher{},
+		Coll:      "progress",
+		OpTimeout: time.Nanosecond,
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("uid", "test-user-123")
+		c.Next()
+	})
+	router.GET("/progress", progressHandler.GetProgress)
+
+	req := httptest.NewRequest("GET", "/progress?month=2025-10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+// TestGetAttachmentReturnsGatewayTimeoutWhenOpDeadlineFires covers
+// progress_attachments.go's fetchOwnedProgress, which previously fetched the
+// progress entry with no op deadline at all.
+func TestGetAttachmentReturnsGatewayTimeoutWhenOpDeadlineFires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fsClient, err := setupFirestoreEmulator()
+	if err != nil {
+		t.Fatalf("Failed to setup Firestore emulator: %v", err)
+	}
+	defer fsClient.Close()
+
+	progressHandler := &ProgressHandler{
+		Fs:        fsClient,
+		Pub:       &pubsub.Publisher{},
+		Store:     storage.NoopStore{},
+		Coll:      "progress",
+		OpTimeout: time.Nanosecond,
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("uid", "test-user-123")
+		c.Next()
+	})
+	router.GET("/progress/:progressId/attachments/:key", progressHandler.GetAttachment)
+
+	req := httptest.NewRequest("GET", "/progress/progress-1/attachments/some-key", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
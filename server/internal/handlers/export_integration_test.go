@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+const exportedEntryCount = 500
+
+// peakTrackingWriter is an http.ResponseWriter that tracks the most bytes
+// ever buffered between two Flush calls, so a test can tell whether a
+// streaming handler is actually flushing per record rather than writing
+// its whole response in one shot.
+type peakTrackingWriter struct {
+	header     http.Header
+	status     int
+	body       bytes.Buffer
+	sinceFlush int
+	peak       int
+}
+
+func newPeakTrackingWriter() *peakTrackingWriter {
+	return &peakTrackingWriter{header: make(http.Header)}
+}
+
+func (w *peakTrackingWriter) Header() http.Header { return w.header }
+
+func (w *peakTrackingWriter) WriteHeader(status int) { w.status = status }
+
+func (w *peakTrackingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	w.sinceFlush += len(b)
+	if w.sinceFlush > w.peak {
+		w.peak = w.sinceFlush
+	}
+	return len(b), nil
+}
+
+func (w *peakTrackingWriter) Flush() { w.sinceFlush = 0 }
+
+// TestExportProgressStreamsWithBoundedBuffering seeds a few hundred progress
+// entries, streams them back via GET /progress/export, and checks both that
+// every entry (plus a trailing cursor record) arrives intact and that the
+// handler is genuinely flushing per record - the peak amount of unflushed
+// data should be close to one record, not the whole result set.
+func TestExportProgressStreamsWithBoundedBuffering(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var goal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < exportedEntryCount; i++ {
+		entry := openapi.Progress{
+			GoalId:       goal.Id,
+			MinutesSpent: 10,
+			Date:         openapi_types.Date{Time: base.AddDate(0, 0, i)},
+		}
+		body, _ := json.Marshal(entry)
+		req := httptest.NewRequest("POST", "/progress", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	out := newPeakTrackingWriter()
+	req = httptest.NewRequest("GET", "/progress/export", nil)
+	router.ServeHTTP(out, req)
+
+	assert.Equal(t, http.StatusOK, out.status)
+	assert.Equal(t, "application/x-ndjson", out.header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(bytes.NewReader(out.body.Bytes()))
+	var entries []openapi.Progress
+	var cursor struct {
+		Cursor string `json:"cursor"`
+	}
+	var lastLine string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		lastLine = line
+		var p openapi.Progress
+		if err := json.Unmarshal([]byte(line), &p); err == nil && p.Id != "" {
+			entries = append(entries, p)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.NotEmpty(t, lastLine)
+	require.NoError(t, json.Unmarshal([]byte(lastLine), &cursor))
+
+	assert.Len(t, entries, exportedEntryCount)
+	assert.Equal(t, base.AddDate(0, 0, exportedEntryCount-1).Format(time.RFC3339), cursor.Cursor)
+
+	avgRecordSize := out.body.Len() / (exportedEntryCount + 1)
+	assert.Less(t, out.peak, avgRecordSize*5,
+		"peak unflushed buffer (%d bytes) should stay close to one record (~%d bytes), not the whole %d-record response",
+		out.peak, avgRecordSize, exportedEntryCount)
+}
+
+// TestExportProgressSSEFraming checks the Accept: text/event-stream branch
+// frames each record as a "data: " line, independent of the NDJSON
+// buffering assertions covered above.
+func TestExportProgressSSEFraming(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var goal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+
+	entry := openapi.Progress{
+		GoalId:       goal.Id,
+		MinutesSpent: 15,
+		Date:         openapi_types.Date{Time: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	body, _ := json.Marshal(entry)
+	req = httptest.NewRequest("POST", "/progress", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest("GET", "/progress/export", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "data: ")
+	assert.Contains(t, rec.Body.String(), `"cursor"`)
+}
+
+// TestExportYearlySummaryResumesFromSinceCursor checks that ?since excludes
+// entries at or before the cursor, matching the resume contract described
+// in ExportYearlySummary's doc comment.
+func TestExportYearlySummaryResumesFromSinceCursor(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var goal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+
+	for _, d := range []time.Time{
+		time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		entry := openapi.Progress{GoalId: goal.Id, MinutesSpent: 20, Date: openapi_types.Date{Time: d}}
+		body, _ := json.Marshal(entry)
+		req := httptest.NewRequest("POST", "/progress", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	since := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	req = httptest.NewRequest("GET", "/summary/yearly/export?year=2022&since="+since, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	scanner := bufio.NewScanner(bytes.NewReader(rec.Body.Bytes()))
+	var count int
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		var p openapi.Progress
+		if err := json.Unmarshal([]byte(line), &p); err == nil && p.Id != "" {
+			count++
+		}
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 2, count) // June and November entries only; February is before since
+}
@@ -1,38 +1,149 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scosta/goal-app/internal/clock"
+	appfirestore "github.com/scosta/goal-app/internal/firestore"
+	"github.com/scosta/goal-app/internal/metrics"
 	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/notify"
 	"github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/schedule"
 )
 
+// defaultOpTimeout bounds how long a single Firestore operation may run
+// before a handler gives up and reports 504, so a slow backend can't hang
+// a request indefinitely.
+const defaultOpTimeout = 5 * time.Second
+
 // Handler struct contains dependencies
 
 type GoalHandler struct {
-	Fs   *firestore.Client
-	Pub  *pubsub.Publisher
-	Coll string
+	Fs    *firestore.Client
+	Pub   *pubsub.Publisher
+	Tasks TaskEnqueuer
+	Coll  string
+
+	// Clock supplies the current time; defaults to clock.RealClock{} when nil.
+	Clock clock.Clock
+
+	// Notifier is unused here today: goal.created reminder emails are sent
+	// by the async consumer (internal/consumer.ReminderHandler) so this
+	// request stays off the hot path the same way recompute jobs are
+	// enqueued instead of run inline. Kept alongside Clock/Pub so all of a
+	// goal's side effects are visible on the handler struct.
+	Notifier notify.Notifier
+
+	// Idempotency, when set, lets POST /goals honor an Idempotency-Key
+	// header: a retried create with the same key and body returns the
+	// original response instead of creating a second goal. Nil disables it.
+	Idempotency *IdempotencyStore
+
+	// OpTimeout bounds each Firestore operation; defaults to
+	// defaultOpTimeout when zero.
+	OpTimeout time.Duration
+
+	// Metrics records query timings and internal errors for this handler's
+	// endpoints.
+	Metrics *metrics.Metrics
+}
+
+func (h *GoalHandler) clock() clock.Clock {
+	if h.Clock != nil {
+		return h.Clock
+	}
+	return clock.RealClock{}
+}
+
+func (h *GoalHandler) opTimeout() time.Duration {
+	if h.OpTimeout > 0 {
+		return h.OpTimeout
+	}
+	return defaultOpTimeout
 }
 
 // POST /goals
+//
+// Honors an Idempotency-Key header when h.Idempotency is set: a retry with
+// the same key and body returns the original 201 response instead of
+// creating a second goal; the same key reused with a different body is
+// rejected with 409. Concurrent requests racing on the same key are kept
+// from both creating a goal by IdempotencyStore.Reserve's create-only
+// write - only one can win the reservation, so the loser either replays
+// the winner's response or, if the winner hasn't finished yet, is told to
+// retry.
 func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Request.Body.Close()
+
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	var bodyHash string
+	if idempotencyKey != "" && h.Idempotency != nil {
+		bodyHash = HashRequestBody(body)
+		rec, found, err := h.Idempotency.Reserve(c.Request.Context(), idempotencyKey, bodyHash)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if found {
+			if rec.BodyHash != bodyHash {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key already used with a different request body"})
+				return
+			}
+			if rec.State != idempotencyCompleted {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key request already in progress, retry shortly"})
+				return
+			}
+			// A replay isn't a fresh creation, so it reports 200 even
+			// though the original call got 201.
+			c.Data(http.StatusOK, "application/json", rec.Response)
+			return
+		}
+	}
+
 	var input openapi.Goal
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := json.Unmarshal(body, &input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	uid := c.GetString("uid") // set by auth middleware
 	input.Id = uuid.New().String()
 	input.UserId = uid
-	input.CreatedAt = time.Now()
+	input.UserEmail = c.GetString("email")
+	input.CreatedAt = h.clock().Now()
+	if input.Frequency == "" {
+		input.Frequency = schedule.FrequencyDaily
+	}
 
-	_, err := h.Fs.Collection(h.Coll).Doc(input.Id).Set(c.Request.Context(), input)
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	doc := h.Fs.Collection(h.Coll).Doc(input.Id)
+	err = appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := doc.Set(ctx, input)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out writing goal"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -42,12 +153,25 @@ func (h *GoalHandler) CreateGoal(c *gin.Context) {
 		"type":    "goal.created",
 		"payload": input,
 	})
-	c.JSON(http.StatusCreated, input)
+
+	responseBody, err := json.Marshal(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if idempotencyKey != "" && h.Idempotency != nil {
+		if err := h.Idempotency.Complete(c.Request.Context(), idempotencyKey, http.StatusCreated, responseBody); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.Data(http.StatusCreated, "application/json", responseBody)
 }
 
 // GET /goals
 func (h *GoalHandler) ListGoals(c *gin.Context) {
 	uid := c.GetString("uid")
+	done := h.Metrics.TimeQuery(h.Coll, "list")
 	iter := h.Fs.Collection(h.Coll).Where("userId", "==", uid).Documents(c.Request.Context())
 	var goals []openapi.Goal
 	for {
@@ -58,7 +182,124 @@ func (h *GoalHandler) ListGoals(c *gin.Context) {
 		var g openapi.Goal
 		if err := doc.DataTo(&g); err == nil {
 			goals = append(goals, g)
+		} else {
+			h.Metrics.InternalErrorsTotal.WithLabelValues("goal_handler.list_goals").Inc()
 		}
 	}
+	done(len(goals))
 	c.JSON(http.StatusOK, goals)
 }
+
+// PUT /goals/{goalId}
+//
+// Upserts by the caller-supplied goalId: creates the goal (201, publishing
+// goal.created) if it doesn't exist yet, or replaces it (200) if it does.
+// Publishes goal.rescheduled on an update when Frequency/FrequencyMetadata
+// change, so consumers (e.g. reminder scheduling) can react without polling.
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	goalId := c.Param("goalId")
+
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	goalDoc := h.Fs.Collection(h.Coll).Doc(goalId)
+
+	var snap *firestore.DocumentSnapshot
+	err := appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		snap, err = goalDoc.Get(ctx)
+		return err
+	})
+	creating := status.Code(err) == codes.NotFound
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out loading goal"})
+		return
+	}
+	if err != nil && !creating {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existing openapi.Goal
+	if !creating {
+		if err := snap.DataTo(&existing); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if existing.UserId != c.GetString("uid") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to modify this goal"})
+			return
+		}
+	}
+
+	var updateData openapi.Goal
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateData.Id = goalId
+	if creating {
+		updateData.UserId = c.GetString("uid")
+		updateData.UserEmail = c.GetString("email")
+		updateData.CreatedAt = h.clock().Now()
+	} else {
+		updateData.UserId = existing.UserId
+		updateData.UserEmail = existing.UserEmail
+		updateData.CreatedAt = existing.CreatedAt
+	}
+	if updateData.Frequency == "" {
+		updateData.Frequency = schedule.FrequencyDaily
+	}
+
+	rescheduled := !creating && (updateData.Frequency != existing.Frequency ||
+		!reflect.DeepEqual(updateData.FrequencyMetadata, existing.FrequencyMetadata))
+
+	err = appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := goalDoc.Set(ctx, updateData)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out writing goal"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case creating:
+		_ = h.Pub.Publish(c.Request.Context(), map[string]interface{}{
+			"type":    "goal.created",
+			"payload": updateData,
+		})
+	case rescheduled:
+		_ = h.Pub.Publish(c.Request.Context(), map[string]interface{}{
+			"type":    "goal.rescheduled",
+			"payload": updateData,
+		})
+	}
+
+	respStatus := http.StatusOK
+	if creating {
+		respStatus = http.StatusCreated
+	}
+	c.JSON(respStatus, updateData)
+}
+
+// POST /goals/{goalId}/recompute
+//
+// Enqueues a RecomputeGoalStats job instead of blocking the request; the
+// worker (cmd/worker) recalculates streaks via consumer.ComputeGoalStats,
+// the same helper the pub/sub consumer uses to keep goal_stats up to date
+// after each progress event, then writes the result back to goal_stats.
+func (h *GoalHandler) RecomputeGoalStats(c *gin.Context) {
+	goalId := c.Param("goalId")
+
+	taskId, err := h.Tasks.EnqueueRecomputeGoalStats(c.Request.Context(), goalId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"taskId": taskId})
+}
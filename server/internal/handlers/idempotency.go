@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyState tracks whether a reserved key's request has finished, so
+// a concurrent caller that loses the Reserve race can tell "still in
+// flight, nothing to replay yet" apart from "done, here's the response".
+type idempotencyState string
+
+const (
+	idempotencyPending   idempotencyState = "pending"
+	idempotencyCompleted idempotencyState = "completed"
+)
+
+// IdempotencyRecord is what IdempotencyStore persists for a given
+// Idempotency-Key: a hash of the request body that produced it (so a retry
+// with a different body can be rejected) and the response to replay once
+// State reaches idempotencyCompleted.
+type IdempotencyRecord struct {
+	Key        string           `firestore:"key"`
+	BodyHash   string           `firestore:"bodyHash"`
+	State      idempotencyState `firestore:"state"`
+	RespStatus int              `firestore:"respStatus"`
+	Response   []byte           `firestore:"response"`
+	CreatedAt  time.Time        `firestore:"createdAt"`
+	// ExpiresAt is read by a Firestore TTL policy configured on this
+	// collection's "expiresAt" field; nothing in this package enforces it.
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// IdempotencyStore persists Idempotency-Key -> response mappings so
+// retrying a POST with the same key returns the original response instead
+// of creating a duplicate resource.
+type IdempotencyStore struct {
+	Fs   *firestore.Client
+	Coll string
+	// TTL controls how long a key is honored before Firestore's TTL policy
+	// reclaims it; defaults to defaultIdempotencyTTL when zero.
+	TTL time.Duration
+}
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+func (s *IdempotencyStore) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return defaultIdempotencyTTL
+}
+
+// Lookup returns the stored record for key, if any. Callers that are about
+// to act on a miss (i.e. create the resource the key guards) should use
+// Reserve instead - a bare Lookup miss says nothing about whether another
+// request is doing the same thing concurrently.
+func (s *IdempotencyStore) Lookup(ctx context.Context, key string) (IdempotencyRecord, bool, error) {
+	doc, err := s.Fs.Collection(s.Coll).Doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return IdempotencyRecord{}, false, nil
+	}
+	if err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+	var rec IdempotencyRecord
+	if err := doc.DataTo(&rec); err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+	return rec, true, nil
+}
+
+// Reserve atomically claims key for a request with bodyHash via a
+// create-only write, so two concurrent requests carrying the same
+// Idempotency-Key can't both observe a Lookup miss and create duplicate
+// resources: Firestore accepts exactly one Doc.Create for a given key, and
+// the loser gets back whatever the winner left there instead.
+//
+// found is false when this call won the race and the caller should go
+// ahead and do the work, finishing with Complete. found is true when
+// another request already holds the key; rec.State says whether that
+// request already finished (idempotencyCompleted, rec.Response ready to
+// replay) or is still running (idempotencyPending, nothing to replay yet -
+// the caller should tell the client to retry rather than redo the work).
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, bodyHash string) (rec IdempotencyRecord, found bool, err error) {
+	now := time.Now().UTC()
+	placeholder := IdempotencyRecord{
+		Key:       key,
+		BodyHash:  bodyHash,
+		State:     idempotencyPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl()),
+	}
+	_, err = s.Fs.Collection(s.Coll).Doc(key).Create(ctx, placeholder)
+	if err == nil {
+		return placeholder, false, nil
+	}
+	if status.Code(err) != codes.AlreadyExists {
+		return IdempotencyRecord{}, false, err
+	}
+
+	existing, ok, lookupErr := s.Lookup(ctx, key)
+	if lookupErr != nil {
+		return IdempotencyRecord{}, false, lookupErr
+	}
+	if !ok {
+		// Create lost the race against a concurrent reservation that has
+		// since been reclaimed (e.g. by the TTL policy); nothing left to
+		// replay or conflict against.
+		return IdempotencyRecord{}, false, fmt.Errorf("idempotency key %q vanished after a concurrent reservation", key)
+	}
+	return existing, true, nil
+}
+
+// Complete finishes a key this store's own Reserve call won, recording the
+// response a request produced so a concurrent or later Reserve can replay
+// it instead of redoing the work.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, respStatus int, response []byte) error {
+	_, err := s.Fs.Collection(s.Coll).Doc(key).Update(ctx, []firestore.Update{
+		{Path: "state", Value: idempotencyCompleted},
+		{Path: "respStatus", Value: respStatus},
+		{Path: "response", Value: response},
+	})
+	return err
+}
+
+// HashRequestBody returns a stable hash of a request body, used to tell a
+// legitimate retry (identical body) from a conflicting reuse of the same
+// Idempotency-Key.
+func HashRequestBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+func TestCreateGoalIdempotencyKey(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+
+	post := func(body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "goal-key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// First call creates.
+	w := post(goalJSON)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	firstBody := w.Body.Bytes()
+
+	// Repeat call with the identical body replays the original response.
+	w = post(goalJSON)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, firstBody, w.Body.Bytes())
+
+	// Same key, different body is a conflict.
+	other := goalData
+	other.Title = "Learn French"
+	otherJSON, _ := json.Marshal(other)
+	w = post(otherJSON)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRecordProgressIdempotencyKey(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var createdGoal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdGoal))
+
+	progressData := openapi.Progress{
+		GoalId:       createdGoal.Id,
+		MinutesSpent: 45,
+		Date:         openapi_types.Date{Time: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	progressJSON, _ := json.Marshal(progressData)
+
+	post := func(body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/progress", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "progress-key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w = post(progressJSON)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	firstBody := w.Body.Bytes()
+
+	w = post(progressJSON)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, firstBody, w.Body.Bytes())
+
+	other := progressData
+	other.MinutesSpent = 60
+	otherJSON, _ := json.Marshal(other)
+	w = post(otherJSON)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdateGoalUpsert(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalId := "upsert-goal-1"
+	goalData := openapi.Goal{
+		Id:                  goalId,
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+
+	// PUT against an ID that doesn't exist creates it.
+	req := httptest.NewRequest("PUT", "/goals/"+goalId, bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, goalId, created.Id)
+	assert.Equal(t, "Learn Spanish", created.Title)
+
+	// PUT against the same ID now replaces it.
+	goalData.Title = "Learn Spanish Fluently"
+	goalJSON, _ = json.Marshal(goalData)
+	req = httptest.NewRequest("PUT", "/goals/"+goalId, bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var replaced openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &replaced))
+	assert.Equal(t, "Learn Spanish Fluently", replaced.Title)
+	assert.Equal(t, created.CreatedAt, replaced.CreatedAt)
+}
+
+func TestUpdateProgressUpsert(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var createdGoal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdGoal))
+
+	progressId := "upsert-progress-1"
+	progressData := openapi.Progress{
+		Id:           progressId,
+		GoalId:       createdGoal.Id,
+		MinutesSpent: 45,
+		Date:         openapi_types.Date{Time: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	progressJSON, _ := json.Marshal(progressData)
+
+	// PUT against an ID that doesn't exist creates it.
+	req = httptest.NewRequest("PUT", "/progress/"+progressId, bytes.NewBuffer(progressJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created openapi.Progress
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, progressId, created.Id)
+	assert.Equal(t, 45, created.MinutesSpent)
+
+	// PUT against the same ID now replaces it.
+	progressData.MinutesSpent = 60
+	progressJSON, _ = json.Marshal(progressData)
+	req = httptest.NewRequest("PUT", "/progress/"+progressId, bytes.NewBuffer(progressJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var replaced openapi.Progress
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &replaced))
+	assert.Equal(t, 60, replaced.MinutesSpent)
+	assert.Equal(t, created.CreatedAt, replaced.CreatedAt)
+}
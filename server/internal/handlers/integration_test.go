@@ -17,7 +17,10 @@ import (
 
 	"cloud.google.com/go/firestore"
 	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scosta/goal-app/internal/metrics"
 	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/observability"
 	"github.com/scosta/goal-app/internal/pubsub"
 )
 
@@ -34,7 +37,14 @@ func setupFirestoreEmulator() (*firestore.Client, error) {
 	return client, nil
 }
 
-func setupTestRouter() (*gin.Engine, *firestore.Client, *pubsub.Publisher) {
+// setupTestRouter builds the test router. obsCfg optionally wires the
+// observability middleware with a caller-supplied Config (e.g. a
+// MemorySink, so a test can inspect what was captured); omitted, every
+// handler still runs through the middleware, just with no sinks attached.
+// Each call gets its own Metrics bound to a fresh registry (returned so a
+// test can assert against it directly) instead of sharing
+// prometheus.DefaultRegisterer across the whole test binary.
+func setupTestRouter(obsCfg ...observability.Config) (*gin.Engine, *firestore.Client, *pubsub.Publisher, *metrics.Metrics) {
 	gin.SetMode(gin.TestMode)
 
 	// Setup Firestore emulator
@@ -43,31 +53,47 @@ func setupTestRouter() (*gin.Engine, *firestore.Client, *pubsub.Publisher) {
 		panic(fmt.Sprintf("Failed to setup Firestore emulator: %v", err))
 	}
 
+	m := metrics.New(prometheus.NewRegistry())
+
 	// Setup mock publisher
 	publisher := &pubsub.Publisher{}
 
+	idempotencyColl := "idempotency_keys"
+
 	// Create handlers
 	goalHandler := &GoalHandler{
-		Fs:   fsClient,
-		Pub:  publisher,
-		Coll: "goals",
+		Fs:          fsClient,
+		Pub:         publisher,
+		Coll:        "goals",
+		Idempotency: &IdempotencyStore{Fs: fsClient, Coll: idempotencyColl},
+		Metrics:     m,
 	}
 
 	progressHandler := &ProgressHandler{
-		Fs:   fsClient,
-		Pub:  publisher,
-		Coll: "progress",
+		Fs:          fsClient,
+		Pub:         publisher,
+		Coll:        "progress",
+		Idempotency: &IdempotencyStore{Fs: fsClient, Coll: idempotencyColl},
+		Metrics:     m,
 	}
 
 	summaryHandler := &SummaryHandler{
 		Fs:           fsClient,
 		ProgressColl: "progress",
 		GoalsColl:    "goals",
+		Metrics:      m,
 	}
 
 	// Setup router
 	router := gin.New()
 
+	var cfg observability.Config
+	if len(obsCfg) > 0 {
+		cfg = obsCfg[0]
+	}
+	cfg.Metrics = m
+	router.Use(observability.Middleware(cfg))
+
 	// Add auth middleware (mock)
 	router.Use(func(c *gin.Context) {
 		c.Set("uid", "test-user-123")
@@ -77,15 +103,17 @@ func setupTestRouter() (*gin.Engine, *firestore.Client, *pubsub.Publisher) {
 	// Goals routes
 	goals := router.Group("/goals")
 	{
-		goals.POST("", goalHandler.CreateGoal)
-		goals.GET("", goalHandler.ListGoals)
+		goals.POST("", m.Middleware(), goalHandler.CreateGoal)
+		goals.GET("", m.Middleware(), goalHandler.ListGoals)
+		goals.PUT("/:goalId", goalHandler.UpdateGoal)
 	}
 
 	// Progress routes
 	progress := router.Group("/progress")
 	{
-		progress.POST("", progressHandler.RecordProgress)
-		progress.GET("", progressHandler.GetProgress)
+		progress.POST("", m.Middleware(), progressHandler.RecordProgress)
+		progress.GET("", m.Middleware(), progressHandler.GetProgress)
+		progress.GET("/export", m.Middleware(), progressHandler.ExportProgress)
 		progress.GET("/:goalId", progressHandler.GetProgressForGoal)
 		progress.PUT("/:progressId", progressHandler.UpdateProgress)
 		progress.DELETE("/:progressId", progressHandler.DeleteProgress)
@@ -94,15 +122,16 @@ func setupTestRouter() (*gin.Engine, *firestore.Client, *pubsub.Publisher) {
 	// Summary routes
 	summary := router.Group("/summary")
 	{
-		summary.GET("/monthly", summaryHandler.GetMonthlySummary)
-		summary.GET("/yearly", summaryHandler.GetYearlySummary)
+		summary.GET("/monthly", m.Middleware(), summaryHandler.GetMonthlySummary)
+		summary.GET("/yearly", m.Middleware(), summaryHandler.GetYearlySummary)
+		summary.GET("/yearly/export", m.Middleware(), summaryHandler.ExportYearlySummary)
 	}
 
-	return router, fsClient, publisher
+	return router, fsClient, publisher, m
 }
 
 func TestGoalWorkflow(t *testing.T) {
-	router, fsClient, _ := setupTestRouter()
+	router, fsClient, _, _ := setupTestRouter()
 	defer fsClient.Close()
 
 	// Create a goal
@@ -143,7 +172,7 @@ func TestGoalWorkflow(t *testing.T) {
 }
 
 func TestProgressWorkflow(t *testing.T) {
-	router, fsClient, _ := setupTestRouter()
+	router, fsClient, _, _ := setupTestRouter()
 	defer fsClient.Close()
 
 	// First create a goal
@@ -192,15 +221,16 @@ func TestProgressWorkflow(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var progressList []openapi.Progress
-	err = json.Unmarshal(w.Body.Bytes(), &progressList)
+	var page ProgressPage
+	err = json.Unmarshal(w.Body.Bytes(), &page)
 	require.NoError(t, err)
-	assert.Len(t, progressList, 1)
-	assert.Equal(t, 45, progressList[0].MinutesSpent)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, 45, page.Items[0].MinutesSpent)
+	assert.Empty(t, page.NextCursor)
 }
 
 func TestMonthlySummaryCalculation(t *testing.T) {
-	router, fsClient, _ := setupTestRouter()
+	router, fsClient, _, _ := setupTestRouter()
 	defer fsClient.Close()
 
 	// Create a goal
@@ -270,7 +300,7 @@ func TestMonthlySummaryCalculation(t *testing.T) {
 }
 
 func TestYearlySummaryCalculation(t *testing.T) {
-	router, fsClient, _ := setupTestRouter()
+	router, fsClient, _, _ := setupTestRouter()
 	defer fsClient.Close()
 
 	// Create a goal
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// TestMetricsMiddlewareCountsGoalsAndProgressRequests checks that hitting
+// /goals and /progress through setupTestRouter (which wires m.Middleware the
+// same way cmd/api does) increments HTTPRequestsTotal for each route. Each
+// test gets its own registry from setupTestRouter, so this asserts directly
+// against it instead of diffing a shared global counter before and after.
+func TestMetricsMiddlewareCountsGoalsAndProgressRequests(t *testing.T) {
+	router, fsClient, _, m := setupTestRouter()
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+	req := httptest.NewRequest(http.MethodPost, "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var createdGoal openapi.Goal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdGoal))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPRequestsTotal.WithLabelValues("/goals", http.MethodPost, "201")))
+
+	progressData := openapi.Progress{
+		GoalId:       createdGoal.Id,
+		MinutesSpent: 20,
+	}
+	progressJSON, _ := json.Marshal(progressData)
+	req = httptest.NewRequest(http.MethodPost, "/progress", bytes.NewBuffer(progressJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPRequestsTotal.WithLabelValues("/progress", http.MethodPost, "201")))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/observability"
+)
+
+func TestObservabilityMiddlewareCapturesGoalCreation(t *testing.T) {
+	sink := &observability.MemorySink{}
+	router, fsClient, _, _ := setupTestRouter(observability.Config{
+		Sinks:      []observability.Sink{sink},
+		HeaderDeny: []string{"Authorization"},
+		FieldDeny:  []string{"userId"},
+	})
+	defer fsClient.Close()
+
+	goalData := openapi.Goal{
+		Title:               "Learn Spanish",
+		TargetMinutesPerDay: 30,
+		StartDate:           openapi_types.Date{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	goalJSON, _ := json.Marshal(goalData)
+
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBuffer(goalJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-token")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	records := sink.Records()
+	require.Len(t, records, 1)
+	rec := records[0]
+
+	assert.Equal(t, "POST", rec.Method)
+	assert.Equal(t, "/goals", rec.Route)
+	assert.Equal(t, http.StatusCreated, rec.Status)
+	assert.Equal(t, []string{"[REDACTED]"}, rec.Headers["Authorization"])
+
+	var respGoal openapi.Goal
+	require.NoError(t, json.Unmarshal(rec.ResponseBody, &respGoal))
+	assert.Equal(t, "Learn Spanish", respGoal.Title)
+
+	var respFields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.ResponseBody, &respFields))
+	var redactedUid string
+	require.NoError(t, json.Unmarshal(respFields["userId"], &redactedUid))
+	assert.Equal(t, "[REDACTED]", redactedUid)
+}
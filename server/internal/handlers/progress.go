@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -8,35 +12,123 @@ import (
 	"github.com/google/uuid"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scosta/goal-app/internal/clock"
+	appfirestore "github.com/scosta/goal-app/internal/firestore"
+	"github.com/scosta/goal-app/internal/metrics"
 	openapi "github.com/scosta/goal-app/internal/models"
 	"github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/storage"
 )
 
+// errNotOwner marks a requireOwner failure that has already written its own
+// response; callers just need to stop processing.
+var errNotOwner = errors.New("not owner")
+
 // ProgressHandler struct contains dependencies
 type ProgressHandler struct {
-	Fs   *firestore.Client
-	Pub  *pubsub.Publisher
-	Coll string
+	Fs    *firestore.Client
+	Pub   *pubsub.Publisher
+	Store storage.Store
+	Tasks TaskEnqueuer
+	Coll  string
+
+	// Clock supplies the current time; defaults to clock.RealClock{} when nil.
+	Clock clock.Clock
+
+	// Idempotency, when set, lets POST /progress honor an Idempotency-Key
+	// header the same way GoalHandler.CreateGoal does. Nil disables it.
+	Idempotency *IdempotencyStore
+
+	// OpTimeout bounds each Firestore operation; defaults to
+	// defaultOpTimeout when zero.
+	OpTimeout time.Duration
+
+	// Metrics records query timings and internal errors for this handler's
+	// endpoints.
+	Metrics *metrics.Metrics
+}
+
+func (h *ProgressHandler) clock() clock.Clock {
+	if h.Clock != nil {
+		return h.Clock
+	}
+	return clock.RealClock{}
+}
+
+func (h *ProgressHandler) opTimeout() time.Duration {
+	if h.OpTimeout > 0 {
+		return h.OpTimeout
+	}
+	return defaultOpTimeout
 }
 
 // POST /progress
+//
+// Honors an Idempotency-Key header when h.Idempotency is set; see
+// GoalHandler.CreateGoal for the shared semantics.
 func (h *ProgressHandler) RecordProgress(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Request.Body.Close()
+
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	var bodyHash string
+	if idempotencyKey != "" && h.Idempotency != nil {
+		bodyHash = HashRequestBody(body)
+		rec, found, err := h.Idempotency.Reserve(c.Request.Context(), idempotencyKey, bodyHash)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if found {
+			if rec.BodyHash != bodyHash {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key already used with a different request body"})
+				return
+			}
+			if rec.State != idempotencyCompleted {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key request already in progress, retry shortly"})
+				return
+			}
+			// A replay isn't a fresh creation, so it reports 200 even
+			// though the original call got 201.
+			c.Data(http.StatusOK, "application/json", rec.Response)
+			return
+		}
+	}
+
 	var input openapi.Progress
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := json.Unmarshal(body, &input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	_ = c.GetString("uid") // set by auth middleware
+	uid := c.GetString("uid")
 	input.Id = uuid.New().String()
-	input.CreatedAt = time.Now()
+	input.UserId = uid
+	input.CreatedAt = h.clock().Now()
 
 	// Calculate target met status based on goal's target minutes per day
 	// This would need to fetch the goal to get targetMinutesPerDay
 	// For now, we'll set it to false and calculate in the summary
 	input.TargetMet = false
 
-	_, err := h.Fs.Collection(h.Coll).Doc(input.Id).Set(c.Request.Context(), input)
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	doc := h.Fs.Collection(h.Coll).Doc(input.Id)
+	err = appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := doc.Set(ctx, input)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out writing progress entry"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -48,12 +140,23 @@ func (h *ProgressHandler) RecordProgress(c *gin.Context) {
 		"payload": input,
 	})
 
-	c.JSON(http.StatusCreated, input)
+	responseBody, err := json.Marshal(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if idempotencyKey != "" && h.Idempotency != nil {
+		if err := h.Idempotency.Complete(c.Request.Context(), idempotencyKey, http.StatusCreated, responseBody); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.Data(http.StatusCreated, "application/json", responseBody)
 }
 
 // GET /progress
 func (h *ProgressHandler) GetProgress(c *gin.Context) {
-	_ = c.GetString("uid")
+	uid := c.GetString("uid")
 	month := c.Query("month") // Format: YYYY-MM
 
 	if month == "" {
@@ -70,32 +173,29 @@ func (h *ProgressHandler) GetProgress(c *gin.Context) {
 	startDate := time.Date(yearNum, time.Month(monthNum), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0) // Next month
 
-	// Query all progress entries and filter by date
-	iter := h.Fs.Collection(h.Coll).Documents(c.Request.Context())
-
-	var progress []openapi.Progress
-	for {
-		doc, err := iter.Next()
-		if err != nil {
-			break
-		}
-		var p openapi.Progress
-		if err := doc.DataTo(&p); err == nil {
-			// Convert openapi_types.Date to time.Time and check if it's within the month
-			progressDate := p.Date.Time
-			if (progressDate.After(startDate) || progressDate.Equal(startDate)) && progressDate.Before(endDate) {
-				progress = append(progress, p)
-			}
-		}
+	base := h.Fs.Collection(h.Coll).Where("userId", "==", uid)
+	page, err := h.fetchProgressPage(c, base, startDate, endDate)
+	if err != nil {
+		respondProgressPageError(c, err)
+		return
 	}
-
-	c.JSON(http.StatusOK, progress)
+	c.JSON(http.StatusOK, page)
 }
 
 // GET /progress/{goalId}
+//
+// Also serves the year-scoped browse endpoint GET /progress/{year}: Gin's
+// router only allows one wildcard name per path depth, so /progress/:year
+// shares this route and is dispatched to GetProgressForYear when the
+// segment looks like a bare 4-digit year rather than a goal ID.
 func (h *ProgressHandler) GetProgressForGoal(c *gin.Context) {
-	_ = c.GetString("uid")
-	goalId := c.Param("goalId")
+	goalId := c.Param("progressId")
+	if isYearSegment(goalId) {
+		h.GetProgressForYear(c)
+		return
+	}
+
+	uid := c.GetString("uid")
 	month := c.Query("month")
 
 	if month == "" {
@@ -113,64 +213,89 @@ func (h *ProgressHandler) GetProgressForGoal(c *gin.Context) {
 	startDate := time.Date(yearNum, time.Month(monthNum), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0) // Next month
 
-	// Query progress entries for specific goal and filter by date
-	iter := h.Fs.Collection(h.Coll).
-		Where("goalId", "==", goalId).
-		Documents(c.Request.Context())
-
-	var progress []openapi.Progress
-	for {
-		doc, err := iter.Next()
-		if err != nil {
-			break
-		}
-		var p openapi.Progress
-		if err := doc.DataTo(&p); err == nil {
-			// Convert openapi_types.Date to time.Time and check if it's within the month
-			progressDate := p.Date.Time
-			if (progressDate.After(startDate) || progressDate.Equal(startDate)) && progressDate.Before(endDate) {
-				progress = append(progress, p)
-			}
-		}
+	base := h.Fs.Collection(h.Coll).Where("goalId", "==", goalId).Where("userId", "==", uid)
+	page, err := h.fetchProgressPage(c, base, startDate, endDate)
+	if err != nil {
+		respondProgressPageError(c, err)
+		return
 	}
-
-	c.JSON(http.StatusOK, progress)
+	c.JSON(http.StatusOK, page)
 }
 
 // PUT /progress/{progressId}
+//
+// Upserts by the caller-supplied progressId: creates the entry (201,
+// publishing progress.recorded) if it doesn't exist yet, or replaces it
+// (200, publishing progress.updated) if it does.
 func (h *ProgressHandler) UpdateProgress(c *gin.Context) {
 	progressId := c.Param("progressId")
-	_ = c.GetString("uid")
+
+	owner, creating, err := h.resolveOwner(c, progressId)
+	if err != nil {
+		return
+	}
 
 	var updateData openapi.Progress
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	updateData.Id = progressId
+	if creating {
+		updateData.UserId = c.GetString("uid")
+		updateData.CreatedAt = h.clock().Now()
+	} else {
+		updateData.UserId = owner
+	}
 
-	// Update the progress entry
-	_, err := h.Fs.Collection(h.Coll).Doc(progressId).Set(c.Request.Context(), updateData)
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	err = appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := h.Fs.Collection(h.Coll).Doc(progressId).Set(ctx, updateData)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out writing progress entry"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Publish event
+	eventType := "progress.updated"
+	respStatus := http.StatusOK
+	if creating {
+		eventType = "progress.recorded"
+		respStatus = http.StatusCreated
+	}
 	_ = h.Pub.Publish(c.Request.Context(), map[string]interface{}{
-		"type":    "progress.updated",
+		"type":    eventType,
 		"payload": updateData,
 	})
 
-	c.JSON(http.StatusOK, updateData)
+	c.JSON(respStatus, updateData)
 }
 
 // DELETE /progress/{progressId}
 func (h *ProgressHandler) DeleteProgress(c *gin.Context) {
 	progressId := c.Param("progressId")
-	_ = c.GetString("uid")
+
+	if _, err := h.requireOwner(c, progressId); err != nil {
+		return
+	}
 
 	// Delete the progress entry
-	_, err := h.Fs.Collection(h.Coll).Doc(progressId).Delete(c.Request.Context())
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	err := appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := h.Fs.Collection(h.Coll).Doc(progressId).Delete(ctx)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out deleting progress entry"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -187,6 +312,95 @@ func (h *ProgressHandler) DeleteProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Progress entry deleted"})
 }
 
+// POST /progress/recompute?month=YYYY-MM
+//
+// Enqueues a RecomputeMonth job instead of blocking the request; see
+// GoalHandler.RecomputeGoalStats for the shared worker path.
+func (h *ProgressHandler) RecomputeMonth(c *gin.Context) {
+	month := c.Query("month")
+	if err := validateMonthFormat(month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	taskId, err := h.Tasks.EnqueueRecomputeMonth(c.Request.Context(), month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"taskId": taskId})
+}
+
+// requireOwner fetches progressId and writes a 404/403 response and returns
+// a non-nil error if it doesn't exist or isn't owned by the caller's uid.
+// On success it returns the document's owning uid.
+func (h *ProgressHandler) requireOwner(c *gin.Context, progressId string) (string, error) {
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	var snap *firestore.DocumentSnapshot
+	err := appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		snap, err = h.Fs.Collection(h.Coll).Doc(progressId).Get(ctx)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out loading progress entry"})
+		return "", err
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "progress entry not found"})
+		return "", err
+	}
+	var existing openapi.Progress
+	if err := snap.DataTo(&existing); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return "", err
+	}
+	if existing.UserId != c.GetString("uid") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to modify this progress entry"})
+		return "", errNotOwner
+	}
+	return existing.UserId, nil
+}
+
+// resolveOwner is requireOwner's upsert-tolerant counterpart for
+// UpdateProgress: a missing document isn't an error, it just means the PUT
+// is creating rather than replacing.
+func (h *ProgressHandler) resolveOwner(c *gin.Context, progressId string) (owner string, creating bool, err error) {
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	var snap *firestore.DocumentSnapshot
+	err = appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		snap, err = h.Fs.Collection(h.Coll).Doc(progressId).Get(ctx)
+		return err
+	})
+	if status.Code(err) == codes.NotFound {
+		return "", true, nil
+	}
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out loading progress entry"})
+		return "", false, err
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return "", false, err
+	}
+	var existing openapi.Progress
+	if err := snap.DataTo(&existing); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return "", false, err
+	}
+	if existing.UserId != c.GetString("uid") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to modify this progress entry"})
+		return "", false, errNotOwner
+	}
+	return existing.UserId, false, nil
+}
+
 func parseYearMonth(month string) (int, int, error) {
 	t, err := time.Parse("2006-01", month)
 	if err != nil {
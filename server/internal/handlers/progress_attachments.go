@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"cloud.google.com/go/firestore"
+
+	appfirestore "github.com/scosta/goal-app/internal/firestore"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// defaultAttachmentURLTTL is how long a presigned attachment URL stays valid.
+const defaultAttachmentURLTTL = 15 * time.Minute
+
+// fetchOwnedProgress fetches progressId and writes a 404/403/504 response
+// and returns a non-nil error if it doesn't exist, isn't owned by the
+// caller's uid, or the op deadline fires - the same contract as
+// ProgressHandler.requireOwner, but returning the full entry rather than
+// just the owning uid, since every attachment endpoint needs to read or
+// rewrite Attachments.
+func (h *ProgressHandler) fetchOwnedProgress(c *gin.Context, progressId string) (openapi.Progress, error) {
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	var snap *firestore.DocumentSnapshot
+	err := appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		snap, err = h.Fs.Collection(h.Coll).Doc(progressId).Get(ctx)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out loading progress entry"})
+		return openapi.Progress{}, err
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "progress entry not found"})
+		return openapi.Progress{}, err
+	}
+	var progress openapi.Progress
+	if err := snap.DataTo(&progress); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return openapi.Progress{}, err
+	}
+	if progress.UserId != c.GetString("uid") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this progress entry's attachments"})
+		return openapi.Progress{}, errNotOwner
+	}
+	return progress, nil
+}
+
+// POST /api/progress/:progressId/attachments
+func (h *ProgressHandler) UploadAttachment(c *gin.Context) {
+	progressId := c.Param("progressId")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	progress, err := h.fetchOwnedProgress(c, progressId)
+	if err != nil {
+		return
+	}
+
+	key := fmt.Sprintf("progress/%s/%s-%s", progressId, uuid.New().String(), fileHeader.Filename)
+	contentType := fileHeader.Header.Get("Content-Type")
+
+	putCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	err = appfirestore.Do(putCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		return h.Store.Put(ctx, key, file, fileHeader.Size, contentType)
+	})
+	cancel()
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out uploading attachment"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var url string
+	urlCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	err = appfirestore.Do(urlCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		url, err = h.Store.PresignedURL(ctx, key, defaultAttachmentURLTTL)
+		return err
+	})
+	cancel()
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out presigning attachment URL"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment := openapi.Attachment{
+		Key:         key,
+		URL:         url,
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+	}
+	progress.Attachments = append(progress.Attachments, attachment)
+
+	setCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	err = appfirestore.Do(setCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := h.Fs.Collection(h.Coll).Doc(progressId).Set(ctx, progress)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out saving attachment"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	_ = h.Pub.Publish(c.Request.Context(), map[string]interface{}{
+		"type": "progress.attachment.added",
+		"payload": map[string]interface{}{
+			"progressId": progressId,
+			"attachment": attachment,
+		},
+	})
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GET /api/progress/:progressId/attachments/:key
+func (h *ProgressHandler) GetAttachment(c *gin.Context) {
+	progressId := c.Param("progressId")
+
+	if _, err := h.fetchOwnedProgress(c, progressId); err != nil {
+		return
+	}
+
+	key := attachmentKey(progressId, c.Param("key"))
+
+	var url string
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	err := appfirestore.Do(opCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		var err error
+		url, err = h.Store.PresignedURL(ctx, key, defaultAttachmentURLTTL)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out presigning attachment URL"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// DELETE /api/progress/:progressId/attachments/:key
+func (h *ProgressHandler) DeleteAttachment(c *gin.Context) {
+	progressId := c.Param("progressId")
+	key := attachmentKey(progressId, c.Param("key"))
+
+	progress, err := h.fetchOwnedProgress(c, progressId)
+	if err != nil {
+		return
+	}
+
+	delCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	err = appfirestore.Do(delCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		return h.Store.Delete(ctx, key)
+	})
+	cancel()
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out deleting attachment"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remaining := progress.Attachments[:0]
+	for _, a := range progress.Attachments {
+		if a.Key != key {
+			remaining = append(remaining, a)
+		}
+	}
+	progress.Attachments = remaining
+
+	setCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+	err = appfirestore.Do(setCtx, appfirestore.DefaultBudget(), func(ctx context.Context) error {
+		_, err := h.Fs.Collection(h.Coll).Doc(progressId).Set(ctx, progress)
+		return err
+	})
+	if errors.Is(err, appfirestore.ErrBudgetExhausted) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out saving attachment removal"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	_ = h.Pub.Publish(c.Request.Context(), map[string]interface{}{
+		"type": "progress.attachment.removed",
+		"payload": map[string]interface{}{
+			"progressId": progressId,
+			"key":        key,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment removed"})
+}
+
+// attachmentKey rebuilds the full object-store key from the progressId and
+// the opaque :key path segment (the "{uuid}-{filename}" suffix).
+func attachmentKey(progressId, keySuffix string) string {
+	return fmt.Sprintf("progress/%s/%s", progressId, keySuffix)
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	appfirestore "github.com/scosta/goal-app/internal/firestore"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// defaultProgressPageLimit is used when the caller doesn't supply ?limit=.
+const defaultProgressPageLimit = 50
+
+// errQueryTimedOut marks a fetchProgressPage/ExportProgress query that gave
+// up because its WithOpDeadline context fired before the query finished;
+// callers report this as 504 instead of the 400 they use for bad input.
+var errQueryTimedOut = errors.New("progress query timed out")
+
+// ProgressPage is a cursor-paginated page of progress entries. NextCursor is
+// empty once the caller has reached the end of the range.
+type ProgressPage struct {
+	Items      []openapi.Progress `json:"items"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// fetchProgressPage runs a date-range query (plus whatever filters are
+// already applied to base, e.g. goalId) against Firestore and returns one
+// page of results ordered by date, honoring ?limit= and ?cursor=.
+//
+// This replaces scanning the whole collection in Go: the date bounds and
+// pagination both happen server-side via Where/StartAfter, which requires a
+// composite index on (date ASC) plus whatever equality filter base adds
+// (e.g. userId ASC, goalId ASC, date ASC) - see docs/firestore-indexes.md.
+func (h *ProgressHandler) fetchProgressPage(c *gin.Context, base firestore.Query, startDate, endDate time.Time) (ProgressPage, error) {
+	limit := defaultProgressPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return ProgressPage{}, fmt.Errorf("invalid limit parameter")
+		}
+		limit = parsed
+	}
+
+	query := base.
+		Where("date", ">=", startDate).
+		Where("date", "<", endDate).
+		OrderBy("date", firestore.Asc).
+		Limit(limit + 1)
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		cursorDate, err := time.Parse(time.RFC3339, cursor)
+		if err != nil {
+			return ProgressPage{}, fmt.Errorf("invalid cursor parameter")
+		}
+		query = query.StartAfter(cursorDate)
+	}
+
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	iter := query.Documents(opCtx)
+	var items []openapi.Progress
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			break
+		}
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err == nil {
+			items = append(items, p)
+		}
+	}
+	if opCtx.Err() != nil {
+		return ProgressPage{}, errQueryTimedOut
+	}
+
+	page := ProgressPage{}
+	if len(items) > limit {
+		page.NextCursor = items[limit-1].Date.Time.Format(time.RFC3339)
+		items = items[:limit]
+	}
+	page.Items = items
+	return page, nil
+}
+
+// GET /progress/export?since=<RFC3339>
+//
+// Streams every progress entry for the caller ordered by date, oldest
+// first, as newline-delimited JSON (or Server-Sent Events if the caller
+// sends Accept: text/event-stream), flushing each record as it comes off
+// the Firestore iterator instead of buffering the full result like
+// GetProgress does. A trailing {"cursor":"..."} record carries the date of
+// the last entry streamed, so a client can resume with the same value as
+// ?since to pick up where it left off.
+func (h *ProgressHandler) ExportProgress(c *gin.Context) {
+	uid := c.GetString("uid")
+
+	since := time.Time{}
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+		since = parsed
+	}
+
+	// Unlike fetchProgressPage, a fired deadline here can't turn into a 504:
+	// newRecordStream has likely already written a 200 and some records by
+	// the time the iterator notices. WithOpDeadline still bounds how long a
+	// stuck query can hold the connection open - it just ends the stream
+	// (with whatever cursor it got to) instead of failing the request.
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	query := h.Fs.Collection(h.Coll).Where("userId", "==", uid)
+	if !since.IsZero() {
+		query = query.Where("date", ">", since)
+	}
+	iter := query.OrderBy("date", firestore.Asc).Documents(opCtx)
+	defer iter.Stop()
+
+	stream := newRecordStream(c)
+	cursor := since
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			h.Metrics.InternalErrorsTotal.WithLabelValues("progress_handler.export_progress").Inc()
+			break
+		}
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err != nil {
+			h.Metrics.InternalErrorsTotal.WithLabelValues("progress_handler.export_progress").Inc()
+			continue
+		}
+		if err := stream.writeJSON(p); err != nil {
+			// Client went away; stop producing records.
+			return
+		}
+		cursor = p.Date.Time
+	}
+	_ = stream.writeJSON(gin.H{"cursor": cursor.Format(time.RFC3339)})
+}
+
+// respondProgressPageError writes the right status for a fetchProgressPage
+// error: 504 if its WithOpDeadline context timed out, 400 for anything else
+// (bad limit/cursor parameters).
+func respondProgressPageError(c *gin.Context, err error) {
+	if errors.Is(err, errQueryTimedOut) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out loading progress"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// isYearSegment reports whether s looks like a bare 4-digit year rather
+// than a goal ID, letting GetProgressForGoal's route also serve year-scoped
+// browsing at the same path depth.
+func isYearSegment(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GetProgressForYear serves GET /progress/{year}, routed through
+// GetProgressForGoal (see isYearSegment).
+func (h *ProgressHandler) GetProgressForYear(c *gin.Context) {
+	uid := c.GetString("uid")
+	year := c.Param("progressId")
+
+	if err := validateYearFormat(year); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	yearNum, _ := strconv.Atoi(year)
+
+	startDate := time.Date(yearNum, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(yearNum+1, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	base := h.Fs.Collection(h.Coll).Where("userId", "==", uid)
+	page, err := h.fetchProgressPage(c, base, startDate, endDate)
+	if err != nil {
+		respondProgressPageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetProgressForMonth serves GET /progress/{year}/{month}, registered as
+// /progress/:progressId/:month so it shares a route tree with
+// GetProgressForGoal (see isYearSegment); the first segment is always a
+// year here.
+func (h *ProgressHandler) GetProgressForMonth(c *gin.Context) {
+	uid := c.GetString("uid")
+	year := c.Param("progressId")
+
+	if err := validateYearFormat(year); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	monthNum, err := strconv.Atoi(c.Param("month"))
+	if err != nil || monthNum < 1 || monthNum > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be between 01 and 12"})
+		return
+	}
+	if err := validateMonthFormat(fmt.Sprintf("%s-%02d", year, monthNum)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	yearNum, _ := strconv.Atoi(year)
+	startDate := time.Date(yearNum, time.Month(monthNum), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, 0)
+
+	base := h.Fs.Collection(h.Coll).Where("userId", "==", uid)
+	page, err := h.fetchProgressPage(c, base, startDate, endDate)
+	if err != nil {
+		respondProgressPageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/schedule"
+)
+
+// StreakOptions configures how CalculateStreak interprets a progress history.
+type StreakOptions struct {
+	// TargetMet, when set, only counts days where MinutesSpent >= TargetMinutesPerDay
+	// toward the streak.
+	TargetMet           bool
+	TargetMinutesPerDay int
+
+	// GracePeriodDays allows that many consecutive missed days inside a run
+	// without resetting the streak. Only applies when Frequency is empty or
+	// FrequencyDaily; non-daily frequencies use the schedule itself to
+	// decide which gaps are expected (see Frequency below).
+	GracePeriodDays int
+
+	// EndDate anchors the current streak; defaults to time.Now() in
+	// Timezone when zero.
+	EndDate time.Time
+
+	// Timezone normalizes entries to the user's local calendar day instead
+	// of UTC, so e.g. an 11pm entry doesn't roll into the next day's streak
+	// for someone west of UTC. Defaults to UTC when nil.
+	Timezone *time.Location
+
+	// Frequency and FrequencyMetadata describe the goal's recurrence (see
+	// internal/schedule). When set to anything other than FrequencyDaily, a
+	// gap between two tracked days only breaks the streak if the schedule
+	// actually expected practice on one of the skipped days - so a "3x per
+	// week" goal doesn't break its streak on its off days. ScheduleReference
+	// anchors the schedule (normally the goal's start date).
+	Frequency         schedule.FrequencyType
+	FrequencyMetadata schedule.FrequencyMetadata
+	ScheduleReference time.Time
+}
+
+func (opts StreakOptions) location() *time.Location {
+	if opts.Timezone != nil {
+		return opts.Timezone
+	}
+	return time.UTC
+}
+
+// CalculateStreak returns the current and longest consecutive streaks for a
+// goal's progress history. Entries are normalized to calendar days in
+// opts.Timezone and deduplicated before the streak is computed.
+func CalculateStreak(entries []openapi.Progress, opts StreakOptions) (currentStreak, longestStreak int) {
+	days := uniqueSortedDays(entries, opts)
+	if len(days) == 0 {
+		return 0, 0
+	}
+
+	longestStreak = 1
+	run := 1
+	for i := 1; i < len(days); i++ {
+		if opts.continues(days[i-1], days[i]) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longestStreak {
+			longestStreak = run
+		}
+	}
+
+	endDate := opts.EndDate
+	if endDate.IsZero() {
+		endDate = time.Now().In(opts.location())
+	}
+	endDate = normalizeDay(endDate, opts.location())
+
+	currentStreak = currentStreakFrom(days, endDate, opts)
+	return currentStreak, longestStreak
+}
+
+// dayFollows reports whether b continues a's run, i.e. b falls within
+// graceDays+1 days after a (a gap of 1 day is a plain consecutive day; a
+// larger gap is only a continuation if it fits inside the grace period).
+func dayFollows(a, b time.Time, graceDays int) bool {
+	gap := int(math.Round(b.Sub(a).Hours() / 24))
+	return gap >= 1 && gap <= graceDays+1
+}
+
+// continues reports whether b extends a run that started (or passed
+// through) a. For FrequencyDaily (or unset) goals this is the plain
+// gap+grace-period check; for other frequencies, a gap only breaks the
+// streak if the schedule expected practice on a day that was skipped.
+func (opts StreakOptions) continues(a, b time.Time) bool {
+	if opts.Frequency == "" || opts.Frequency == schedule.FrequencyDaily {
+		return dayFollows(a, b, opts.GracePeriodDays)
+	}
+	if !b.After(a) {
+		return false
+	}
+	missed := schedule.ExpectedDates(opts.Frequency, opts.FrequencyMetadata, opts.ScheduleReference, a.AddDate(0, 0, 1), b)
+	return len(missed) == 0
+}
+
+// activeAt reports whether a run ending at last is still alive as of
+// endDate, i.e. nothing the schedule expected was missed in between.
+func (opts StreakOptions) activeAt(last, endDate time.Time) bool {
+	if opts.Frequency == "" || opts.Frequency == schedule.FrequencyDaily {
+		gap := int(math.Round(endDate.Sub(last).Hours() / 24))
+		return gap >= 0 && gap <= opts.GracePeriodDays+1
+	}
+	if endDate.Before(last) {
+		return false
+	}
+	missed := schedule.ExpectedDates(opts.Frequency, opts.FrequencyMetadata, opts.ScheduleReference, last.AddDate(0, 0, 1), endDate)
+	return len(missed) == 0
+}
+
+// currentStreakFrom walks backwards from endDate, counting the contiguous
+// run present in the sorted, deduped days slice.
+func currentStreakFrom(days []time.Time, endDate time.Time, opts StreakOptions) int {
+	last := days[len(days)-1]
+	if !opts.activeAt(last, endDate) {
+		return 0
+	}
+
+	count := 1
+	for i := len(days) - 1; i > 0; i-- {
+		if !opts.continues(days[i-1], days[i]) {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// uniqueSortedDays normalizes Progress.Date to calendar days in opts'
+// timezone, optionally filtering to days the target was met, and returns
+// the unique days ascending.
+func uniqueSortedDays(entries []openapi.Progress, opts StreakOptions) []time.Time {
+	loc := opts.location()
+	seen := make(map[time.Time]bool)
+	for _, e := range entries {
+		if opts.TargetMet && e.MinutesSpent < opts.TargetMinutesPerDay {
+			continue
+		}
+		seen[normalizeDay(e.Date.Time, loc)] = true
+	}
+
+	days := make([]time.Time, 0, len(seen))
+	for d := range seen {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// normalizeDay truncates t to a calendar day in loc.
+func normalizeDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
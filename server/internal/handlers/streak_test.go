@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/schedule"
+	"github.com/stretchr/testify/assert"
+)
+
+func dateEntry(y int, m time.Month, d, minutesSpent int) openapi.Progress {
+	return openapi.Progress{
+		MinutesSpent: minutesSpent,
+		Date:         openapi_types.Date{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestCalculateStreak(t *testing.T) {
+	tests := []struct {
+		name            string
+		entries         []openapi.Progress
+		opts            StreakOptions
+		expectedCurrent int
+		expectedLongest int
+	}{
+		{
+			name:            "empty input",
+			entries:         nil,
+			opts:            StreakOptions{EndDate: time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)},
+			expectedCurrent: 0,
+			expectedLongest: 0,
+		},
+		{
+			name: "single day ending today",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 10, 30),
+			},
+			opts:            StreakOptions{EndDate: time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)},
+			expectedCurrent: 1,
+			expectedLongest: 1,
+		},
+		{
+			name: "non-contiguous days",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 1, 30),
+				dateEntry(2025, 10, 2, 30),
+				dateEntry(2025, 10, 3, 30),
+				dateEntry(2025, 10, 7, 30),
+				dateEntry(2025, 10, 8, 30),
+			},
+			opts:            StreakOptions{EndDate: time.Date(2025, 10, 8, 0, 0, 0, 0, time.UTC)},
+			expectedCurrent: 2,
+			expectedLongest: 3,
+		},
+		{
+			name: "current streak broken by an old gap before today",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 1, 30),
+				dateEntry(2025, 10, 2, 30),
+				dateEntry(2025, 10, 3, 30),
+			},
+			opts:            StreakOptions{EndDate: time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)},
+			expectedCurrent: 0,
+			expectedLongest: 3,
+		},
+		{
+			name: "grace period bridges a single missed day",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 1, 30),
+				dateEntry(2025, 10, 2, 30),
+				// Oct 3 missed
+				dateEntry(2025, 10, 4, 30),
+				dateEntry(2025, 10, 5, 30),
+			},
+			opts: StreakOptions{
+				GracePeriodDays: 1,
+				EndDate:         time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+			},
+			expectedCurrent: 4,
+			expectedLongest: 4,
+		},
+		{
+			name: "target-met filter excludes days below target",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 1, 30),
+				dateEntry(2025, 10, 2, 5), // below target, excluded
+				dateEntry(2025, 10, 3, 30),
+			},
+			opts: StreakOptions{
+				TargetMet:           true,
+				TargetMinutesPerDay: 20,
+				EndDate:             time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC),
+			},
+			expectedCurrent: 1,
+			expectedLongest: 1,
+		},
+		{
+			name: "duplicate entries on the same day count once",
+			entries: []openapi.Progress{
+				dateEntry(2025, 10, 1, 10),
+				dateEntry(2025, 10, 1, 20),
+				dateEntry(2025, 10, 2, 10),
+			},
+			opts:            StreakOptions{EndDate: time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
+			expectedCurrent: 2,
+			expectedLongest: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := CalculateStreak(tt.entries, tt.opts)
+			assert.Equal(t, tt.expectedCurrent, current, "current streak")
+			assert.Equal(t, tt.expectedLongest, longest, "longest streak")
+		})
+	}
+}
+
+// tzEntry builds a Progress entry from an instant expressed in loc, rather
+// than the UTC-midnight shorthand dateEntry uses - needed to exercise
+// entries that land on different calendar days depending on timezone.
+func tzEntry(loc *time.Location, y int, m time.Month, d, hour, minutesSpent int) openapi.Progress {
+	return openapi.Progress{
+		MinutesSpent: minutesSpent,
+		Date:         openapi_types.Date{Time: time.Date(y, m, d, hour, 0, 0, 0, loc)},
+	}
+}
+
+func TestCalculateStreakTimezoneCrossesMidnight(t *testing.T) {
+	// 11pm Oct 1 in Los Angeles is already Oct 2 in UTC. Normalizing in UTC
+	// would collapse these into a single Oct 2 day; normalizing in the
+	// goal's timezone keeps them as two consecutive local days.
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	entries := []openapi.Progress{
+		tzEntry(la, 2025, 10, 1, 23, 30),
+		tzEntry(la, 2025, 10, 2, 1, 30),
+	}
+	opts := StreakOptions{
+		Timezone: la,
+		EndDate:  time.Date(2025, 10, 2, 12, 0, 0, 0, la),
+	}
+	current, longest := CalculateStreak(entries, opts)
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 2, longest)
+}
+
+func TestCalculateStreakAcrossDSTFallBack(t *testing.T) {
+	// Nov 2, 2025 is the US fall-back DST transition, so the local day
+	// straddling it is 25 hours long in absolute time. The streak should
+	// still count one day per calendar date, not be thrown off by the
+	// extra hour.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	entries := []openapi.Progress{
+		tzEntry(ny, 2025, 11, 1, 9, 30),
+		tzEntry(ny, 2025, 11, 2, 9, 30), // the 25-hour day
+		tzEntry(ny, 2025, 11, 3, 9, 30),
+	}
+	opts := StreakOptions{
+		Timezone: ny,
+		EndDate:  time.Date(2025, 11, 3, 20, 0, 0, 0, ny),
+	}
+	current, longest := CalculateStreak(entries, opts)
+	assert.Equal(t, 3, current)
+	assert.Equal(t, 3, longest)
+}
+
+func TestCalculateStreakNonDailyFrequencyToleratesScheduledOffDays(t *testing.T) {
+	// A Mon/Wed/Fri goal shouldn't break its streak over Tue/Thu/weekend -
+	// those days were never expected to have progress.
+	meta := schedule.FrequencyMetadata{Days: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}
+	reference := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC) // a Wednesday
+
+	entries := []openapi.Progress{
+		dateEntry(2025, 10, 1, 30), // Wed
+		dateEntry(2025, 10, 3, 30), // Fri
+		dateEntry(2025, 10, 6, 30), // Mon
+	}
+	opts := StreakOptions{
+		Frequency:         schedule.FrequencyDaysOfWeek,
+		FrequencyMetadata: meta,
+		ScheduleReference: reference,
+		EndDate:           time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC),
+	}
+	current, longest := CalculateStreak(entries, opts)
+	assert.Equal(t, 3, current)
+	assert.Equal(t, 3, longest)
+}
+
+func TestCalculateStreakNonDailyFrequencyBreaksOnMissedExpectedDay(t *testing.T) {
+	// Same Mon/Wed/Fri schedule, but Friday is skipped entirely - that was
+	// an expected day, so the streak should reset at Monday.
+	meta := schedule.FrequencyMetadata{Days: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}
+	reference := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC) // a Wednesday
+
+	entries := []openapi.Progress{
+		dateEntry(2025, 10, 1, 30), // Wed
+		// Oct 3 (Fri) missed
+		dateEntry(2025, 10, 6, 30), // Mon
+	}
+	opts := StreakOptions{
+		Frequency:         schedule.FrequencyDaysOfWeek,
+		FrequencyMetadata: meta,
+		ScheduleReference: reference,
+		EndDate:           time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC),
+	}
+	current, longest := CalculateStreak(entries, opts)
+	assert.Equal(t, 1, current)
+	assert.Equal(t, 1, longest)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordStream writes one JSON object per call to a client, flushing
+// immediately after each so a handler iterating a Firestore cursor never
+// has to buffer more than the current record - see ExportProgress and
+// SummaryHandler.ExportYearlySummary. Format is chosen once, up front, from
+// the request's Accept header: "text/event-stream" gets SSE "data: " frames,
+// everything else gets newline-delimited JSON.
+type recordStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	sse     bool
+}
+
+// newRecordStream selects NDJSON or SSE framing, writes the response headers
+// and status, and returns a stream ready for writeJSON calls. It must be
+// called before any other write to c.Writer.
+func newRecordStream(c *gin.Context) *recordStream {
+	sse := strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+	if sse {
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.WriteHeader(http.StatusOK)
+
+	flusher, _ := c.Writer.(http.Flusher)
+	return &recordStream{w: c.Writer, flusher: flusher, sse: sse}
+}
+
+// writeJSON marshals v and writes it as one frame, flushing it to the
+// client before returning. A non-nil error means the write failed (most
+// often because the client disconnected) and the caller should stop
+// producing further records.
+func (s *recordStream) writeJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if s.sse {
+		if _, err := s.w.Write([]byte("data: ")); err != nil {
+			return err
+		}
+		if _, err := s.w.Write(b); err != nil {
+			return err
+		}
+		if _, err := s.w.Write([]byte("\n\n")); err != nil {
+			return err
+		}
+	} else {
+		if _, err := s.w.Write(b); err != nil {
+			return err
+		}
+		if _, err := s.w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
@@ -8,7 +8,13 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/scosta/goal-app/internal/clock"
+	appfirestore "github.com/scosta/goal-app/internal/firestore"
+	"github.com/scosta/goal-app/internal/metrics"
 	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/schedule"
 )
 
 // SummaryHandler struct contains dependencies
@@ -16,11 +22,49 @@ type SummaryHandler struct {
 	Fs           *firestore.Client
 	ProgressColl string
 	GoalsColl    string
+
+	// Clock supplies the current time; defaults to clock.RealClock{} when
+	// nil. Not on the hot path today (month/year come from query params),
+	// but kept alongside the other handlers so callers can pin "now" once
+	// these endpoints grow a default-to-current-period behavior.
+	Clock clock.Clock
+
+	// MaxResults caps how many documents a single query collects before it
+	// stops early and reports a result_truncated warning; defaults to
+	// defaultMaxSummaryResults when zero.
+	MaxResults int
+
+	// OpTimeout bounds the Firestore queries behind a single summary
+	// request; defaults to defaultOpTimeout when zero. Unlike the
+	// single-document writes in GoalHandler/ProgressHandler, a timed-out
+	// query here isn't retried - re-running a multi-document aggregation
+	// from scratch costs as much as the original attempt, so Do's retry
+	// budget isn't worth it; GetMonthlySummary/GetYearlySummary just report
+	// 504 once the deadline fires.
+	OpTimeout time.Duration
+
+	// Metrics records query timings and internal errors for this handler's
+	// endpoints.
+	Metrics *metrics.Metrics
+}
+
+func (h *SummaryHandler) clock() clock.Clock {
+	if h.Clock != nil {
+		return h.Clock
+	}
+	return clock.RealClock{}
+}
+
+func (h *SummaryHandler) opTimeout() time.Duration {
+	if h.OpTimeout > 0 {
+		return h.OpTimeout
+	}
+	return defaultOpTimeout
 }
 
 // GET /summary/monthly
 func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
-	_ = c.GetString("uid")
+	uid := c.GetString("uid")
 	month := c.Query("month") // Format: YYYY-MM
 
 	if month == "" {
@@ -44,41 +88,38 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 	startDate := time.Date(year, time.Month(monthNum), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0) // Next month
 
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
 	// Get all progress entries for the month
+	progressDone := h.Metrics.TimeQuery(h.ProgressColl, "get-monthly-summary")
 	progressIter := h.Fs.Collection(h.ProgressColl).
+		Where("userId", "==", uid).
 		Where("createdAt", ">=", startDate).
 		Where("createdAt", "<", endDate).
-		Documents(c.Request.Context())
+		Documents(opCtx)
 
-	var progressEntries []openapi.Progress
-	for {
-		doc, err := progressIter.Next()
-		if err != nil {
-			break
-		}
-		var p openapi.Progress
-		if err := doc.DataTo(&p); err == nil {
-			progressEntries = append(progressEntries, p)
-		}
-	}
+	progressEntries, progressWarnings := collectProgress(progressIter, h.maxResults(), "summary_handler.get_monthly_summary", h.Metrics)
+	progressDone(len(progressEntries))
 
 	// Get all goals for the user
+	goalsDone := h.Metrics.TimeQuery(h.GoalsColl, "get-monthly-summary")
 	goalsIter := h.Fs.Collection(h.GoalsColl).
-		Where("userId", "==", "test-user").
-		Documents(c.Request.Context())
+		Where("userId", "==", uid).
+		Documents(opCtx)
 
-	var goals []openapi.Goal
-	for {
-		doc, err := goalsIter.Next()
-		if err != nil {
-			break
-		}
-		var g openapi.Goal
-		if err := doc.DataTo(&g); err == nil {
-			goals = append(goals, g)
-		}
+	goals, goalWarnings := collectGoals(goalsIter, h.maxResults(), "summary_handler.get_monthly_summary", h.Metrics)
+	goalsDone(len(goals))
+
+	if opCtx.Err() != nil {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out building monthly summary"})
+		return
 	}
 
+	warnings := make([]Warning, 0, len(progressWarnings)+len(goalWarnings))
+	warnings = append(warnings, progressWarnings...)
+	warnings = append(warnings, goalWarnings...)
+
 	// Group progress by goal and calculate success rates
 	goalProgress := make(map[string][]openapi.Progress)
 	for _, p := range progressEntries {
@@ -100,10 +141,16 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 	for _, goal := range goals {
 		progress := goalProgress[goal.Id]
 
-		// Calculate success rate for this goal
-		daysInMonth := int(endDate.Sub(startDate).Hours() / 24)
+		// Score against how many days this goal actually expects practice
+		// (per its recurrence schedule), not every day in the month - a
+		// "3x per week" goal shouldn't be judged against 30 days.
+		expectedDates := schedule.ExpectedDates(goal.Frequency, goal.FrequencyMetadata, goal.StartDate.Time, startDate, endDate)
+		expectedDays := len(expectedDates)
+		if expectedDays == 0 {
+			expectedDays = 1
+		}
 		daysWithProgress := len(progress)
-		successRate := float64(daysWithProgress) / float64(daysInMonth) * 100
+		successRate := float64(daysWithProgress) / float64(expectedDays) * 100
 
 		// Calculate total minutes spent on this goal
 		goalMinutesSpent := 0
@@ -112,13 +159,13 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 		}
 		totalMinutesSpent += goalMinutesSpent
 
-		// Calculate current streak (simplified)
-		currentStreak := 0
-		if len(progress) > 0 {
-			// Sort by date and calculate consecutive days
-			// For now, just use the number of entries as a proxy
-			currentStreak = len(progress)
-		}
+		currentStreak, longestStreak := CalculateStreak(progress, StreakOptions{
+			Timezone:          goalTimezone(goal),
+			Frequency:         goal.Frequency,
+			FrequencyMetadata: goal.FrequencyMetadata,
+			ScheduleReference: goal.StartDate.Time,
+			EndDate:           endDate,
+		})
 
 		goalProgressList = append(goalProgressList, struct {
 			GoalId        string  `json:"goalId"`
@@ -133,7 +180,7 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 			SuccessRate:   successRate,
 			MinutesSpent:  goalMinutesSpent,
 			CurrentStreak: currentStreak,
-			LongestStreak: currentStreak, // Simplified
+			LongestStreak: longestStreak,
 		})
 	}
 
@@ -156,6 +203,7 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 			"totalGoals":         totalGoals,
 			"averageSuccessRate": averageSuccessRate,
 		},
+		"warnings": warnings,
 	}
 
 	c.JSON(http.StatusOK, report)
@@ -163,7 +211,7 @@ func (h *SummaryHandler) GetMonthlySummary(c *gin.Context) {
 
 // GET /summary/yearly
 func (h *SummaryHandler) GetYearlySummary(c *gin.Context) {
-	_ = c.GetString("uid")
+	uid := c.GetString("uid")
 	year := c.Query("year") // Format: YYYY
 
 	if year == "" {
@@ -180,22 +228,23 @@ func (h *SummaryHandler) GetYearlySummary(c *gin.Context) {
 	startDate := time.Date(yearNum, 1, 1, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(yearNum+1, 1, 1, 0, 0, 0, 0, time.UTC)
 
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
 	// Get all progress entries for the year
+	progressDone := h.Metrics.TimeQuery(h.ProgressColl, "get-yearly-summary")
 	progressIter := h.Fs.Collection(h.ProgressColl).
+		Where("userId", "==", uid).
 		Where("createdAt", ">=", startDate).
 		Where("createdAt", "<", endDate).
-		Documents(c.Request.Context())
+		Documents(opCtx)
 
-	var progressEntries []openapi.Progress
-	for {
-		doc, err := progressIter.Next()
-		if err != nil {
-			break
-		}
-		var p openapi.Progress
-		if err := doc.DataTo(&p); err == nil {
-			progressEntries = append(progressEntries, p)
-		}
+	progressEntries, warnings := collectProgress(progressIter, h.maxResults(), "summary_handler.get_yearly_summary", h.Metrics)
+	progressDone(len(progressEntries))
+
+	if opCtx.Err() != nil {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "timed out building yearly summary"})
+		return
 	}
 
 	// Group progress by month
@@ -297,7 +346,99 @@ func (h *SummaryHandler) GetYearlySummary(c *gin.Context) {
 			"bestMonth":          bestMonth,
 			"worstMonth":         worstMonth,
 		},
+		"warnings": warnings,
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
+
+// GET /summary/yearly/export?year=YYYY&since=<RFC3339>
+//
+// Streams the raw progress entries behind a yearly summary, oldest first by
+// Date (the same field fetchProgressPage filters and orders by, not the
+// createdAt GetYearlySummary groups its rollups by), as newline-delimited
+// JSON or SSE if the caller sends Accept: text/event-stream - instead of
+// buffering them all like GetYearlySummary does before aggregating. It
+// reports the underlying entries rather than the monthly rollups, since
+// those only exist once the full year has been collected - a partial
+// aggregate isn't a meaningful thing to flush mid stream. A trailing
+// {"cursor":"..."} record carries the date of the last entry sent, for
+// resuming with ?since.
+func (h *SummaryHandler) ExportYearlySummary(c *gin.Context) {
+	uid := c.GetString("uid")
+	year := c.Query("year")
+	if year == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "year parameter is required"})
+		return
+	}
+
+	yearNum, err := strconv.Atoi(year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year format"})
+		return
+	}
+
+	startDate := time.Date(yearNum, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(yearNum+1, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	lowerOp := ">="
+	if raw := c.Query("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since parameter"})
+			return
+		}
+		if !since.Before(startDate) {
+			startDate = since
+			lowerOp = ">"
+		}
+	}
+
+	opCtx, cancel := appfirestore.WithOpDeadline(c.Request.Context(), h.opTimeout())
+	defer cancel()
+
+	iter := h.Fs.Collection(h.ProgressColl).
+		Where("userId", "==", uid).
+		Where("date", lowerOp, startDate).
+		Where("date", "<", endDate).
+		OrderBy("date", firestore.Asc).
+		Documents(opCtx)
+	defer iter.Stop()
+
+	stream := newRecordStream(c)
+	cursor := startDate
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			h.Metrics.InternalErrorsTotal.WithLabelValues("summary_handler.export_yearly_summary").Inc()
+			break
+		}
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err != nil {
+			h.Metrics.InternalErrorsTotal.WithLabelValues("summary_handler.export_yearly_summary").Inc()
+			continue
+		}
+		if err := stream.writeJSON(p); err != nil {
+			// Client went away; stop producing records.
+			return
+		}
+		cursor = p.Date.Time
+	}
+	_ = stream.writeJSON(gin.H{"cursor": cursor.Format(time.RFC3339)})
+}
+
+// goalTimezone resolves goal.Timezone (an IANA name like "America/New_York")
+// to a *time.Location, falling back to UTC if it's unset or invalid.
+func goalTimezone(goal openapi.Goal) *time.Location {
+	if goal.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(goal.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// CalculateMonthlyAggregates sums minutes spent and counts tracked days
+// across a set of progress entries.
+func CalculateMonthlyAggregates(progressEntries []openapi.Progress) (totalMinutes int, daysTracked int) {
+	daysTracked = len(progressEntries)
+	for _, entry := range progressEntries {
+		totalMinutes += entry.MinutesSpent
+	}
+	return totalMinutes, daysTracked
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scosta/goal-app/internal/metrics"
+)
+
+// TestMonthlySummaryReportsDecodeErrorWarning injects a progress document
+// whose minutesSpent field won't decode into openapi.Progress, then checks
+// that GetMonthlySummary still returns 200 with the rest of the data, plus
+// a decode_error warning naming the offending document - instead of the
+// entry silently dropping out of the totals.
+func TestMonthlySummaryReportsDecodeErrorWarning(t *testing.T) {
+	router, fsClient, _, _ := setupTestRouter()
+	defer fsClient.Close()
+
+	ctx := context.Background()
+	_, err := fsClient.Collection("progress").Doc("bad-doc").Set(ctx, map[string]interface{}{
+		"userId":       "test-user-123",
+		"goalId":       "some-goal",
+		"minutesSpent": "not-a-number", // malformed: DataTo expects an int
+		"createdAt":    time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+		"date":         time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/summary/monthly?month=2025-10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+
+	warnings, ok := summary["warnings"].([]interface{})
+	require.True(t, ok, "expected a warnings array in the response")
+	require.NotEmpty(t, warnings)
+
+	first := warnings[0].(map[string]interface{})
+	assert.Equal(t, "decode_error", first["code"])
+	assert.Equal(t, "bad-doc", first["docId"])
+}
+
+// TestMonthlySummaryTruncatesAtMaxResults checks that hitting MaxResults
+// stops collection early and reports result_truncated rather than silently
+// returning a partial, unlabeled result set.
+func TestMonthlySummaryTruncatesAtMaxResults(t *testing.T) {
+	fsClient, err := setupFirestoreEmulator()
+	require.NoError(t, err)
+	defer fsClient.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err := fsClient.Collection("progress").Doc(fmt.Sprintf("progress-%d", i)).Set(ctx, map[string]interface{}{
+			"userId":       "test-user-123",
+			"goalId":       "some-goal",
+			"minutesSpent": 30,
+			"createdAt":    time.Date(2025, 10, 1+i, 0, 0, 0, 0, time.UTC),
+			"date":         time.Date(2025, 10, 1+i, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	summaryHandler := &SummaryHandler{
+		Fs:           fsClient,
+		ProgressColl: "progress",
+		GoalsColl:    "goals",
+		MaxResults:   2,
+		Metrics:      metrics.New(prometheus.NewRegistry()),
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("uid", "test-user-123")
+		c.Next()
+	})
+	router.GET("/summary/monthly", summaryHandler.GetMonthlySummary)
+
+	req := httptest.NewRequest("GET", "/summary/monthly?month=2025-10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+
+	warnings, ok := summary["warnings"].([]interface{})
+	require.True(t, ok)
+
+	var sawTruncated bool
+	for _, warning := range warnings {
+		if warning.(map[string]interface{})["code"] == "result_truncated" {
+			sawTruncated = true
+		}
+	}
+	assert.True(t, sawTruncated, "expected a result_truncated warning")
+}
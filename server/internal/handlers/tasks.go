@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/scosta/goal-app/internal/clock"
+)
+
+// TaskStatus is the client-facing view of an enqueued recompute job.
+type TaskStatus struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TaskEnqueuer abstracts enqueueing recompute jobs and polling their status.
+// GoalHandler and ProgressHandler depend on this interface rather than
+// internal/tasks directly: internal/tasks depends on this package for its
+// calculation helpers, so the dependency can't also run the other way.
+type TaskEnqueuer interface {
+	EnqueueRecomputeGoalStats(ctx context.Context, goalId string) (taskId string, err error)
+	EnqueueRecomputeMonth(ctx context.Context, month string) (taskId string, err error)
+	TaskStatus(ctx context.Context, taskId string) (TaskStatus, error)
+}
+
+// TaskHandler serves GET /tasks/{taskId} for polling recompute job status.
+type TaskHandler struct {
+	Tasks TaskEnqueuer
+
+	// Clock supplies the current time; defaults to clock.RealClock{} when nil.
+	Clock clock.Clock
+}
+
+func (h *TaskHandler) clock() clock.Clock {
+	if h.Clock != nil {
+		return h.Clock
+	}
+	return clock.RealClock{}
+}
+
+// GET /tasks/{taskId}
+func (h *TaskHandler) GetTaskStatus(c *gin.Context) {
+	status, err := h.Tasks.TaskStatus(c.Request.Context(), c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
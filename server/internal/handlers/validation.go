@@ -0,0 +1,41 @@
+package handlers
+
+import "fmt"
+
+// validateMonthFormat validates that month is in YYYY-MM form with a
+// reasonable year and a month number between 01 and 12.
+func validateMonthFormat(month string) error {
+	if len(month) != 7 || month[4] != '-' {
+		return fmt.Errorf("month must be in YYYY-MM format")
+	}
+
+	year := month[:4]
+	monthNum := month[5:]
+
+	// Validate year is numeric and reasonable
+	if year < "2020" || year > "2030" {
+		return fmt.Errorf("year must be between 2020 and 2030")
+	}
+
+	// Validate month is numeric and 01-12
+	if monthNum < "01" || monthNum > "12" {
+		return fmt.Errorf("month must be between 01 and 12")
+	}
+
+	return nil
+}
+
+// validateYearFormat validates that year is a 4-character string between
+// "2020" and "2030", compared as strings; the characters are not checked
+// to be digits.
+func validateYearFormat(year string) error {
+	if len(year) != 4 {
+		return fmt.Errorf("year must be in YYYY format")
+	}
+
+	if year < "2020" || year > "2030" {
+		return fmt.Errorf("year must be between 2020 and 2030")
+	}
+
+	return nil
+}
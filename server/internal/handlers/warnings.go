@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/scosta/goal-app/internal/metrics"
+	openapi "github.com/scosta/goal-app/internal/models"
+)
+
+// Warning codes for the summary endpoints' warnings array, modeled on
+// Prometheus' query API: a 200 carries whatever data it could assemble plus
+// a list of what went wrong along the way, instead of a single bad document
+// silently dropping out of the totals.
+const (
+	WarningIteratorError   = "iterator_error"
+	WarningDecodeError     = "decode_error"
+	WarningResultTruncated = "result_truncated"
+)
+
+// Warning describes a recoverable problem a summary endpoint hit while
+// assembling its response.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	DocID   string `json:"docId,omitempty"`
+}
+
+// defaultMaxSummaryResults caps how many documents a single summary query
+// will collect before it stops early and reports result_truncated, so one
+// very large account can't make these endpoints scan without bound.
+const defaultMaxSummaryResults = 5000
+
+func (h *SummaryHandler) maxResults() int {
+	if h.MaxResults > 0 {
+		return h.MaxResults
+	}
+	return defaultMaxSummaryResults
+}
+
+// collectProgress drains iter into Progress entries, distinguishing a
+// transient iterator failure from a document that wouldn't decode, and
+// stopping (with a result_truncated warning) once max entries are collected.
+func collectProgress(iter *firestore.DocumentIterator, max int, component string, m *metrics.Metrics) ([]openapi.Progress, []Warning) {
+	var entries []openapi.Progress
+	warnings := []Warning{}
+	for {
+		if len(entries) >= max {
+			warnings = append(warnings, Warning{
+				Code:    WarningResultTruncated,
+				Message: fmt.Sprintf("stopped after %d results", max),
+			})
+			break
+		}
+
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			m.InternalErrorsTotal.WithLabelValues(component).Inc()
+			warnings = append(warnings, Warning{Code: WarningIteratorError, Message: err.Error()})
+			break
+		}
+
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err != nil {
+			m.InternalErrorsTotal.WithLabelValues(component).Inc()
+			warnings = append(warnings, Warning{Code: WarningDecodeError, Message: err.Error(), DocID: doc.Ref.ID})
+			continue
+		}
+		entries = append(entries, p)
+	}
+	return entries, warnings
+}
+
+// collectGoals is collectProgress's counterpart for Goal documents.
+func collectGoals(iter *firestore.DocumentIterator, max int, component string, m *metrics.Metrics) ([]openapi.Goal, []Warning) {
+	var entries []openapi.Goal
+	warnings := []Warning{}
+	for {
+		if len(entries) >= max {
+			warnings = append(warnings, Warning{
+				Code:    WarningResultTruncated,
+				Message: fmt.Sprintf("stopped after %d results", max),
+			})
+			break
+		}
+
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			m.InternalErrorsTotal.WithLabelValues(component).Inc()
+			warnings = append(warnings, Warning{Code: WarningIteratorError, Message: err.Error()})
+			break
+		}
+
+		var g openapi.Goal
+		if err := doc.DataTo(&g); err != nil {
+			m.InternalErrorsTotal.WithLabelValues(component).Inc()
+			warnings = append(warnings, Warning{Code: WarningDecodeError, Message: err.Error(), DocID: doc.Ref.ID})
+			continue
+		}
+		entries = append(entries, g)
+	}
+	return entries, warnings
+}
@@ -0,0 +1,112 @@
+// Package metrics registers the Prometheus collectors this service exposes
+// on /metrics: HTTP request counts and latency, Firestore query duration and
+// result counts, Pub/Sub publish outcomes, and build info.
+package metrics
+
+import (
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds every collector this service records to. Callers build one
+// instance per *prometheus.Registry via New instead of collectors binding
+// themselves to the global prometheus.DefaultRegisterer, so e.g. a test can
+// assert against its own registry instead of diffing global counters
+// before and after.
+type Metrics struct {
+	// HTTPRequestsTotal counts handled requests, labeled by route, method,
+	// and status. route is the matched route template (e.g. "/goals/:goalId"),
+	// never the raw path, to keep cardinality bounded.
+	HTTPRequestsTotal *prometheus.CounterVec
+
+	// HTTPRequestDuration observes request latency in seconds for the same
+	// label set as HTTPRequestsTotal.
+	HTTPRequestDuration *prometheus.HistogramVec
+
+	// FirestoreQueryDuration observes how long a Firestore query took,
+	// labeled by collection and operation (e.g. "list", "get-monthly-summary").
+	FirestoreQueryDuration *prometheus.HistogramVec
+
+	// FirestoreQueryResults observes how many documents a Firestore query
+	// returned, so empty result sets and unusually large ones both show up.
+	FirestoreQueryResults *prometheus.HistogramVec
+
+	// PubSubPublishTotal counts publish attempts, labeled by outcome
+	// ("success" or "failure").
+	PubSubPublishTotal *prometheus.CounterVec
+
+	// InternalErrorsTotal counts errors that are swallowed rather than
+	// surfaced to the caller (e.g. a single bad doc.DataTo inside a
+	// collection scan), labeled by the component that observed them, so
+	// data-corruption drift is visible in dashboards instead of silent.
+	InternalErrorsTotal *prometheus.CounterVec
+}
+
+// New builds the full collector set and registers it against reg.
+func New(reg *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		HTTPRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goalapp_http_requests_total",
+			Help: "Total HTTP requests handled, labeled by route, method, and status.",
+		}, []string{"route", "method", "status"}),
+
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goalapp_http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds, labeled by route, method, and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+
+		FirestoreQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goalapp_firestore_query_duration_seconds",
+			Help:    "Firestore query latency in seconds, labeled by collection and operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"collection", "operation"}),
+
+		FirestoreQueryResults: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goalapp_firestore_query_results",
+			Help:    "Documents returned by a Firestore query, labeled by collection and operation.",
+			Buckets: []float64{0, 1, 2, 5, 10, 25, 50, 100, 250},
+		}, []string{"collection", "operation"}),
+
+		PubSubPublishTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goalapp_pubsub_publish_total",
+			Help: "Pub/Sub publish attempts, labeled by outcome.",
+		}, []string{"outcome"}),
+
+		InternalErrorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goalapp_internal_errors_total",
+			Help: "Errors swallowed internally rather than returned to the caller, labeled by component.",
+		}, []string{"component"}),
+	}
+
+	reg.MustRegister(
+		m.HTTPRequestsTotal,
+		m.HTTPRequestDuration,
+		m.FirestoreQueryDuration,
+		m.FirestoreQueryResults,
+		m.PubSubPublishTotal,
+		m.InternalErrorsTotal,
+	)
+
+	recordBuildInfo(reg)
+	return m
+}
+
+func recordBuildInfo(reg *prometheus.Registry) {
+	buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goalapp_build_info",
+		Help: "Always 1; module path, version, and Go version are reported as labels.",
+	}, []string{"path", "version", "goversion"})
+	reg.MustRegister(buildInfo)
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	buildInfo.WithLabelValues(info.Main.Path, version, info.GoVersion).Set(1)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware records HTTPRequestsTotal and HTTPRequestDuration for the
+// routes it's attached to, labeled by the matched route template (not the
+// raw path, so per-ID paths don't blow up label cardinality).
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.HTTPRequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		m.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// TimeQuery starts timing a Firestore query against collection for the
+// given operation (e.g. "list", "get-monthly-summary"). Call the returned
+// func with the number of documents the query ultimately returned once
+// iteration completes.
+func (m *Metrics) TimeQuery(collection, operation string) func(resultCount int) {
+	start := time.Now()
+	return func(resultCount int) {
+		m.FirestoreQueryDuration.WithLabelValues(collection, operation).Observe(time.Since(start).Seconds())
+		m.FirestoreQueryResults.WithLabelValues(collection, operation).Observe(float64(resultCount))
+	}
+}
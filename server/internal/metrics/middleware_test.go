@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareRecordsRequestsByRouteMethodStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New(prometheus.NewRegistry())
+
+	router := gin.New()
+	router.GET("/widgets/:id", m.Middleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets/123", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		m.HTTPRequestsTotal.WithLabelValues("/widgets/:id", http.MethodGet, "200"),
+	))
+}
+
+func TestTimeQueryObservesDurationAndResultCount(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	done := m.TimeQuery("widgets", "list")
+	done(3)
+
+	count := testutil.CollectAndCount(m.FirestoreQueryResults)
+	assert.GreaterOrEqual(t, count, 1)
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+)
+
+// NewNotifierFromEnv builds the Notifier selected by NOTIFIER ("smtp" or
+// "ses"; defaults to "smtp"), configured from its own env vars - mirroring
+// auth.NewConfigFromEnv's env-driven setup.
+func NewNotifierFromEnv() (Notifier, error) {
+	switch os.Getenv("NOTIFIER") {
+	case "ses":
+		return newSESNotifierFromEnv()
+	case "smtp", "":
+		return newSMTPNotifierFromEnv(), nil
+	default:
+		return nil, fmt.Errorf("notify: unknown NOTIFIER %q", os.Getenv("NOTIFIER"))
+	}
+}
+
+// newSMTPNotifierFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_FROM, and
+// optionally SMTP_USER/SMTP_PASSWORD for PLAIN auth.
+func newSMTPNotifierFromEnv() *SMTPNotifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "1025"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "noreply@goal-app.local"
+	}
+
+	var auth smtp.Auth
+	if user := os.Getenv("SMTP_USER"); user != "" {
+		auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return &SMTPNotifier{Addr: host + ":" + port, From: from, Auth: auth}
+}
+
+// newSESNotifierFromEnv reads SES_FROM_ADDRESS (required) and AWS_REGION
+// (defaults to us-east-1); AWS credentials come from the default SDK chain.
+func newSESNotifierFromEnv() (*SESNotifier, error) {
+	from := os.Getenv("SES_FROM_ADDRESS")
+	if from == "" {
+		return nil, fmt.Errorf("notify: SES_FROM_ADDRESS is required when NOTIFIER=ses")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("notify: load AWS config: %w", err)
+	}
+
+	return &SESNotifier{Client: sesv2.NewFromConfig(cfg), From: from}, nil
+}
@@ -0,0 +1,11 @@
+package notify
+
+import "context"
+
+// NoopNotifier discards every message. It lets main.go boot without mail
+// credentials configured, mirroring storage.NoopStore for local/dev runs.
+type NoopNotifier struct{}
+
+func (NoopNotifier) Send(ctx context.Context, msg Message) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package notify sends transactional email through a pluggable backend, so
+// reminder features (internal/consumer.ReminderHandler) don't couple
+// directly to SMTP or a specific cloud provider.
+package notify
+
+import "context"
+
+// Message is a single email to send.
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string // optional; omit for plain-text-only mail
+}
+
+// Notifier sends a Message through some email transport.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMIMEMessagePlainText(t *testing.T) {
+	msg := buildMIMEMessage("from@example.com", Message{
+		To:       "to@example.com",
+		Subject:  "Hello",
+		TextBody: "plain body",
+	})
+
+	body := string(msg)
+	assert.Contains(t, body, "From: from@example.com")
+	assert.Contains(t, body, "To: to@example.com")
+	assert.Contains(t, body, "Subject: Hello")
+	assert.Contains(t, body, "plain body")
+	assert.NotContains(t, body, "multipart/alternative")
+}
+
+func TestBuildMIMEMessageWithHTMLIsMultipart(t *testing.T) {
+	msg := buildMIMEMessage("from@example.com", Message{
+		To:       "to@example.com",
+		Subject:  "Hello",
+		TextBody: "plain body",
+		HTMLBody: "<p>html body</p>",
+	})
+
+	body := string(msg)
+	assert.Contains(t, body, "multipart/alternative")
+	assert.Contains(t, body, "plain body")
+	assert.Contains(t, body, "<p>html body</p>")
+}
+
+func TestNewNotifierFromEnvDefaultsToSMTP(t *testing.T) {
+	os.Unsetenv("NOTIFIER")
+	n, err := NewNotifierFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &SMTPNotifier{}, n)
+}
+
+func TestNewNotifierFromEnvSESRequiresFromAddress(t *testing.T) {
+	os.Setenv("NOTIFIER", "ses")
+	defer os.Unsetenv("NOTIFIER")
+	os.Unsetenv("SES_FROM_ADDRESS")
+
+	_, err := NewNotifierFromEnv()
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "SES_FROM_ADDRESS"))
+}
+
+func TestNewNotifierFromEnvUnknownValueErrors(t *testing.T) {
+	os.Setenv("NOTIFIER", "carrier-pigeon")
+	defer os.Unsetenv("NOTIFIER")
+
+	_, err := NewNotifierFromEnv()
+	require.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SESNotifier sends mail through AWS SES v2, used in production.
+type SESNotifier struct {
+	Client *sesv2.Client
+	From   string
+}
+
+func (n *SESNotifier) Send(ctx context.Context, msg Message) error {
+	body := &types.Body{Text: &types.Content{Data: aws.String(msg.TextBody)}}
+	if msg.HTMLBody != "" {
+		body.Html = &types.Content{Data: aws.String(msg.HTMLBody)}
+	}
+
+	_, err := n.Client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(n.From),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(msg.Subject)},
+				Body:    body,
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+// SMTPNotifier sends mail through a plain SMTP relay - the default for
+// local development (e.g. mailhog/mailcatcher).
+type SMTPNotifier struct {
+	Addr string // host:port
+	From string
+	Auth smtp.Auth
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	return smtp.SendMail(n.Addr, n.Auth, n.From, []string{msg.To}, buildMIMEMessage(n.From, msg))
+}
+
+// buildMIMEMessage builds a minimal RFC 2822 message, multipart/alternative
+// when msg.HTMLBody is set and plain text otherwise.
+func buildMIMEMessage(from string, msg Message) []byte {
+	if msg.HTMLBody == "" {
+		return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+			from, msg.To, msg.Subject, msg.TextBody))
+	}
+
+	const boundary = "goal-app-boundary"
+	return []byte(fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/alternative; boundary=%s\r\n\r\n"+
+			"--%s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n"+
+			"--%s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s\r\n--%s--",
+		from, msg.To, msg.Subject, boundary,
+		boundary, msg.TextBody,
+		boundary, msg.HTMLBody, boundary))
+}
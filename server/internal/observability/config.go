@@ -0,0 +1,27 @@
+package observability
+
+import (
+	"os"
+
+	"github.com/scosta/goal-app/internal/metrics"
+	"github.com/scosta/goal-app/internal/pubsub"
+)
+
+// NewConfigFromEnv builds the Config cmd/api wires into the router: stdout
+// and Pub/Sub sinks are always on, a webhook sink is added only when
+// OBSERVABILITY_WEBHOOK_URL is set, and the Authorization header/userId
+// field are redacted by default - mirroring
+// auth.NewConfigFromEnv/notify.NewNotifierFromEnv's env-driven setup.
+func NewConfigFromEnv(pub *pubsub.Publisher, m *metrics.Metrics) Config {
+	sinks := []Sink{StdoutSink{}, PubSubSink{Pub: pub}}
+	if url := os.Getenv("OBSERVABILITY_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, WebhookSink{URL: url})
+	}
+
+	return Config{
+		Sinks:      sinks,
+		HeaderDeny: []string{"Authorization"},
+		FieldDeny:  []string{"userId"},
+		Metrics:    m,
+	}
+}
@@ -0,0 +1,39 @@
+package observability
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySink is an in-memory Sink for tests, mirroring consumer.MockSource.
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *MemorySink) Emit(ctx context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+// Records returns every Record captured so far.
+func (s *MemorySink) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Record, len(s.records))
+	copy(out, s.records)
+	return out
+}
+
+// Last returns the most recently captured Record, or the zero Record if
+// none have arrived yet.
+func (s *MemorySink) Last() Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.records) == 0 {
+		return Record{}
+	}
+	return s.records[len(s.records)-1]
+}
@@ -0,0 +1,192 @@
+// Package observability captures request/response traffic through a Gin
+// middleware and fans each capture out to pluggable sinks (stdout, a
+// webhook, Pub/Sub), mirroring the consumer package's small-interface-plus-
+// adapters shape rather than hard-coding a single destination.
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/scosta/goal-app/internal/metrics"
+)
+
+// Record is one captured request/response pair, ready to be marshaled and
+// handed to a Sink.
+type Record struct {
+	Timestamp    time.Time           `json:"timestamp"`
+	Method       string              `json:"method"`
+	Path         string              `json:"path"`
+	Route        string              `json:"route"`
+	Query        string              `json:"query,omitempty"`
+	Headers      map[string][]string `json:"headers,omitempty"`
+	RequestBody  json.RawMessage     `json:"requestBody,omitempty"`
+	ResponseBody json.RawMessage     `json:"responseBody,omitempty"`
+	Status       int                 `json:"status"`
+	DurationMs   float64             `json:"durationMs"`
+}
+
+// Sink receives a Record for every captured request. Implementations should
+// not mutate rec.
+type Sink interface {
+	Emit(ctx context.Context, rec Record) error
+}
+
+// redacted replaces the value of any header or JSON field matched by a deny
+// list, so a sink still sees that the field existed without leaking it.
+const redacted = "[REDACTED]"
+
+// Config controls what Middleware captures and where it sends captures.
+type Config struct {
+	Sinks []Sink
+
+	// HeaderAllow, if non-empty, restricts captured headers to this list
+	// (case-insensitive); everything else is dropped rather than redacted.
+	HeaderAllow []string
+	// HeaderDeny redacts the value of any header in this list
+	// (case-insensitive) that survives HeaderAllow.
+	HeaderDeny []string
+	// FieldDeny redacts the value of any top-level JSON field in this list,
+	// applied independently to RequestBody and ResponseBody.
+	FieldDeny []string
+
+	// Metrics records a sink Emit failure under InternalErrorsTotal. Nil is
+	// fine for tests that never hit a failing sink.
+	Metrics *metrics.Metrics
+}
+
+// Middleware captures method, path, query, headers, request body, response
+// body, status, and duration for every request it sees, then emits a
+// Record to every configured sink. Emit errors are counted under
+// InternalErrorsTotal rather than failing the request.
+func Middleware(cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		var reqBody []byte
+		if c.Request.Body != nil {
+			reqBody, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body.Close()
+			c.Request.Body = io.NopCloser(bytes.NewReader(reqBody))
+		}
+
+		tw := &teeWriter{ResponseWriter: c.Writer, buf: &bytes.Buffer{}}
+		c.Writer = tw
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		rec := Record{
+			Timestamp:    start,
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			Route:        route,
+			Query:        c.Request.URL.RawQuery,
+			Headers:      redactHeaders(c.Request.Header, cfg.HeaderAllow, cfg.HeaderDeny),
+			RequestBody:  redactJSON(reqBody, cfg.FieldDeny),
+			ResponseBody: redactJSON(tw.buf.Bytes(), cfg.FieldDeny),
+			Status:       tw.Status(),
+			DurationMs:   float64(time.Since(start)) / float64(time.Millisecond),
+		}
+
+		for _, sink := range cfg.Sinks {
+			if err := sink.Emit(c.Request.Context(), rec); err != nil && cfg.Metrics != nil {
+				cfg.Metrics.InternalErrorsTotal.WithLabelValues("observability.sink").Inc()
+			}
+		}
+	}
+}
+
+// teeWriter copies everything written to the real ResponseWriter into buf as
+// well, so the response body can be captured without buffering it upstream
+// of the handler's normal c.JSON/c.Data write path.
+type teeWriter struct {
+	gin.ResponseWriter
+	buf *bytes.Buffer
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func redactHeaders(h http.Header, allow, deny []string) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+
+	var allowSet map[string]struct{}
+	if len(allow) > 0 {
+		allowSet = toLowerSet(allow)
+	}
+	denySet := toLowerSet(deny)
+
+	out := make(map[string][]string, len(h))
+	for k, v := range h {
+		lower := strings.ToLower(k)
+		if allowSet != nil {
+			if _, ok := allowSet[lower]; !ok {
+				continue
+			}
+		}
+		if _, ok := denySet[lower]; ok {
+			out[k] = []string{redacted}
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactJSON redacts the top-level fields named in deny from a JSON object
+// body. Bodies that aren't a JSON object (arrays, scalars, empty, malformed)
+// are passed through unchanged - redaction only ever makes sense field by
+// field on an object.
+func redactJSON(body []byte, deny []string) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if len(deny) == 0 {
+		return json.RawMessage(body)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return json.RawMessage(body)
+	}
+	redactedValue, _ := json.Marshal(redacted)
+	for _, f := range deny {
+		if _, ok := fields[f]; ok {
+			fields[f] = redactedValue
+		}
+	}
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return json.RawMessage(body)
+	}
+	return json.RawMessage(out)
+}
+
+func toLowerSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,75 @@
+package observability
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareCapturesRequestAndResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sink := &MemorySink{}
+	router := gin.New()
+	router.Use(Middleware(Config{
+		Sinks:      []Sink{sink},
+		HeaderDeny: []string{"Authorization"},
+		FieldDeny:  []string{"uid"},
+	}))
+	router.POST("/goals", func(c *gin.Context) {
+		var body map[string]interface{}
+		require.NoError(t, c.ShouldBindJSON(&body))
+		c.JSON(http.StatusCreated, gin.H{"id": "goal-1", "title": body["title"], "uid": "user-1"})
+	})
+
+	reqBody := `{"title":"Learn Spanish","uid":"user-1"}`
+	req := httptest.NewRequest("POST", "/goals", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	rec := sink.Last()
+	assert.Equal(t, "POST", rec.Method)
+	assert.Equal(t, "/goals", rec.Route)
+	assert.Equal(t, http.StatusCreated, rec.Status)
+
+	assert.Equal(t, []string{redacted}, rec.Headers["Authorization"])
+
+	var reqFields map[string]string
+	require.NoError(t, json.Unmarshal(rec.RequestBody, &reqFields))
+	assert.Equal(t, "Learn Spanish", reqFields["title"])
+	assert.Equal(t, redacted, reqFields["uid"])
+
+	var respFields map[string]string
+	require.NoError(t, json.Unmarshal(rec.ResponseBody, &respFields))
+	assert.Equal(t, "Learn Spanish", respFields["title"])
+	assert.Equal(t, redacted, respFields["uid"])
+
+	// The handler must still have been able to read the body normally.
+	var handlerGoal map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &handlerGoal))
+	assert.Equal(t, "goal-1", handlerGoal["id"])
+}
+
+func TestRedactHeadersAllowList(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret")
+	h.Set("Content-Type", "application/json")
+
+	got := redactHeaders(h, []string{"content-type"}, nil)
+	assert.Equal(t, map[string][]string{"Content-Type": {"application/json"}}, got)
+}
+
+func TestRedactJSONLeavesNonObjectBodiesAlone(t *testing.T) {
+	body := []byte(`[1,2,3]`)
+	assert.Equal(t, json.RawMessage(body), redactJSON(body, []string{"uid"}))
+}
@@ -0,0 +1,76 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/scosta/goal-app/internal/pubsub"
+)
+
+// StdoutSink writes each Record as one line of JSON to Out, defaulting to
+// os.Stdout when Out is nil.
+type StdoutSink struct {
+	Out io.Writer
+}
+
+func (s StdoutSink) Emit(ctx context.Context, rec Record) error {
+	out := s.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
+
+// WebhookSink POSTs each Record as JSON to URL, using Client (defaulting to
+// http.DefaultClient when nil).
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s WebhookSink) Emit(ctx context.Context, rec Record) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("observability: webhook %s returned %d", s.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// PubSubSink publishes each Record through an existing pubsub.Publisher,
+// the same publisher handlers use for domain events.
+type PubSubSink struct {
+	Pub *pubsub.Publisher
+}
+
+func (s PubSubSink) Emit(ctx context.Context, rec Record) error {
+	return s.Pub.Publish(ctx, rec)
+}
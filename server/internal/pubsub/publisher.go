@@ -5,15 +5,18 @@ import (
 	"encoding/json"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/scosta/goal-app/internal/metrics"
 )
 
 type Publisher struct {
-	topic *pubsub.Topic
+	topic   *pubsub.Topic
+	Metrics *metrics.Metrics
 }
 
-func NewPublisher(ctx context.Context, client *pubsub.Client, topicID string) *Publisher {
+func NewPublisher(ctx context.Context, client *pubsub.Client, topicID string, m *metrics.Metrics) *Publisher {
 	topic := client.Topic(topicID)
-	return &Publisher{topic: topic}
+	return &Publisher{topic: topic, Metrics: m}
 }
 
 func (p *Publisher) Publish(ctx context.Context, event interface{}) error {
@@ -24,9 +27,15 @@ func (p *Publisher) Publish(ctx context.Context, event interface{}) error {
 
 	data, err := json.Marshal(event)
 	if err != nil {
+		p.Metrics.PubSubPublishTotal.WithLabelValues("failure").Inc()
 		return err
 	}
 	result := p.topic.Publish(ctx, &pubsub.Message{Data: data})
 	_, err = result.Get(ctx)
-	return err
+	if err != nil {
+		p.Metrics.PubSubPublishTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+	p.Metrics.PubSubPublishTotal.WithLabelValues("success").Inc()
+	return nil
 }
@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+
+	"github.com/scosta/goal-app/internal/metrics"
+)
+
+func TestPublishWithNilTopicIsNoop(t *testing.T) {
+	p := &Publisher{}
+	err := p.Publish(context.Background(), map[string]string{"type": "test"})
+	require.NoError(t, err)
+}
+
+func TestPublishSuccessIncrementsSuccessCounter(t *testing.T) {
+	ctx := context.Background()
+	srv := pstest.NewServer()
+	defer srv.Close()
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.CreateTopic(ctx, "goal-events")
+	require.NoError(t, err)
+
+	m := metrics.New(prometheus.NewRegistry())
+	publisher := NewPublisher(ctx, client, "goal-events", m)
+
+	err = publisher.Publish(ctx, map[string]string{"type": "test"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.PubSubPublishTotal.WithLabelValues("success")))
+}
+
+func TestPublishFailureIncrementsFailureCounter(t *testing.T) {
+	ctx := context.Background()
+	srv := pstest.NewServer()
+	defer srv.Close()
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	require.NoError(t, err)
+	defer client.Close()
+
+	// No topic created, so publishing to it fails.
+	m := metrics.New(prometheus.NewRegistry())
+	publisher := NewPublisher(ctx, client, "nonexistent-topic", m)
+
+	err = publisher.Publish(ctx, map[string]string{"type": "test"})
+	require.Error(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.PubSubPublishTotal.WithLabelValues("failure")))
+}
@@ -0,0 +1,160 @@
+// Package schedule turns a goal's recurrence rule into the concrete set of
+// dates it's expected to be worked on, so success rates can be measured
+// against how often a goal actually asks for practice instead of assuming
+// every day counts.
+package schedule
+
+import (
+	"math"
+	"time"
+)
+
+// FrequencyType is how often a goal expects practice.
+type FrequencyType string
+
+const (
+	FrequencyDaily      FrequencyType = "daily"
+	FrequencyWeekly     FrequencyType = "weekly"
+	FrequencyMonthly    FrequencyType = "monthly"
+	FrequencyInterval   FrequencyType = "interval"
+	FrequencyDaysOfWeek FrequencyType = "days_of_the_week"
+)
+
+// FrequencyMetadata carries the extra parameters a FrequencyType needs.
+// Which fields apply depends on the type:
+//   - FrequencyDaysOfWeek: Days, e.g. Mon/Wed/Fri.
+//   - FrequencyWeekly: Interval, times per week.
+//   - FrequencyMonthly: Interval, times per month; Months optionally
+//     restricts which calendar months the goal is active in.
+//   - FrequencyInterval: Interval + Unit ("day", "week", or "month").
+type FrequencyMetadata struct {
+	Days     []time.Weekday `json:"days,omitempty"`
+	Interval int            `json:"interval,omitempty"`
+	Unit     string         `json:"unit,omitempty"`
+	Months   []int          `json:"months,omitempty"`
+}
+
+// ExpectedDates returns the dates in [start, end) that a goal with the given
+// frequency is expected to be practiced on, anchored to reference (normally
+// the goal's start date). FrequencyDaily and unrecognized types fall back to
+// every day in the window.
+func ExpectedDates(freq FrequencyType, meta FrequencyMetadata, reference, start, end time.Time) []time.Time {
+	reference = normalize(reference)
+	start = normalize(start)
+	end = normalize(end)
+
+	switch freq {
+	case FrequencyDaysOfWeek:
+		return daysOfWeekDates(meta.Days, start, end)
+	case FrequencyWeekly:
+		return spacedDates(reference, start, end, 7, meta.Interval)
+	case FrequencyMonthly:
+		dates := spacedDates(reference, start, end, 30, meta.Interval)
+		if len(meta.Months) == 0 {
+			return dates
+		}
+		return filterByMonth(dates, meta.Months)
+	case FrequencyInterval:
+		return intervalDates(reference, start, end, meta.Unit, meta.Interval)
+	default:
+		return dailyDates(start, end)
+	}
+}
+
+func normalize(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func dailyDates(start, end time.Time) []time.Time {
+	var dates []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+func daysOfWeekDates(days []time.Weekday, start, end time.Time) []time.Time {
+	wanted := make(map[time.Weekday]bool, len(days))
+	for _, d := range days {
+		wanted[d] = true
+	}
+	var dates []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if wanted[d.Weekday()] {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// spacedDates spreads perWindow occurrences evenly across each
+// windowDays-day period starting at reference, e.g. 3 occurrences per 7
+// days lands one roughly every 2.33 days - so a "3x per week" goal scores
+// against ~13 expected days in a 30-day month, not 30.
+func spacedDates(reference, start, end time.Time, windowDays, perWindow int) []time.Time {
+	if perWindow <= 0 {
+		return nil
+	}
+	step := float64(windowDays) / float64(perWindow)
+
+	firstK := 0
+	if offsetDays := start.Sub(reference).Hours() / 24; offsetDays > 0 {
+		firstK = int(math.Floor(offsetDays / step))
+	}
+
+	var dates []time.Time
+	for k := firstK; ; k++ {
+		d := reference.AddDate(0, 0, int(math.Round(float64(k)*step)))
+		if !d.Before(end) {
+			break
+		}
+		if !d.Before(start) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// intervalDates walks forward from reference in steps of interval units,
+// collecting every date that lands inside [start, end).
+func intervalDates(reference, start, end time.Time, unit string, interval int) []time.Time {
+	if interval <= 0 {
+		interval = 1
+	}
+	step := func(t time.Time) time.Time {
+		switch unit {
+		case "week":
+			return t.AddDate(0, 0, 7*interval)
+		case "month":
+			return t.AddDate(0, interval, 0)
+		default: // "day"
+			return t.AddDate(0, 0, interval)
+		}
+	}
+
+	d := reference
+	for d.Before(start) {
+		d = step(d)
+	}
+
+	var dates []time.Time
+	for d.Before(end) {
+		dates = append(dates, d)
+		d = step(d)
+	}
+	return dates
+}
+
+func filterByMonth(dates []time.Time, months []int) []time.Time {
+	wanted := make(map[int]bool, len(months))
+	for _, m := range months {
+		wanted[m] = true
+	}
+	var filtered []time.Time
+	for _, d := range dates {
+		if wanted[int(d.Month())] {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestExpectedDatesDaily(t *testing.T) {
+	dates := ExpectedDates(FrequencyDaily, FrequencyMetadata{}, day(2025, 10, 1), day(2025, 10, 1), day(2025, 11, 1))
+	assert.Len(t, dates, 31)
+}
+
+func TestExpectedDatesDaysOfWeek(t *testing.T) {
+	meta := FrequencyMetadata{Days: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}
+	dates := ExpectedDates(FrequencyDaysOfWeek, meta, day(2025, 10, 1), day(2025, 10, 1), day(2025, 10, 8))
+	for _, d := range dates {
+		switch d.Weekday() {
+		case time.Monday, time.Wednesday, time.Friday:
+		default:
+			t.Fatalf("unexpected weekday in result: %v", d.Weekday())
+		}
+	}
+	assert.Len(t, dates, 3)
+}
+
+func TestExpectedDatesWeeklyThreeTimesPerWeek(t *testing.T) {
+	// A 3x/week goal over a 30-day month should land around 13 expected
+	// days, not 30 - the bug this feature fixes.
+	meta := FrequencyMetadata{Interval: 3}
+	dates := ExpectedDates(FrequencyWeekly, meta, day(2025, 10, 1), day(2025, 10, 1), day(2025, 10, 31))
+	assert.InDelta(t, 13, len(dates), 1)
+}
+
+func TestExpectedDatesMonthlyRestrictedToMonths(t *testing.T) {
+	meta := FrequencyMetadata{Interval: 1, Months: []int{10, 12}}
+	dates := ExpectedDates(FrequencyMonthly, meta, day(2025, 10, 1), day(2025, 10, 1), day(2025, 12, 31))
+	for _, d := range dates {
+		assert.Contains(t, []time.Month{time.October, time.December}, d.Month())
+	}
+}
+
+func TestExpectedDatesIntervalEveryOtherDay(t *testing.T) {
+	meta := FrequencyMetadata{Interval: 2, Unit: "day"}
+	dates := ExpectedDates(FrequencyInterval, meta, day(2025, 10, 1), day(2025, 10, 1), day(2025, 10, 11))
+	assert.Equal(t, []time.Time{
+		day(2025, 10, 1), day(2025, 10, 3), day(2025, 10, 5),
+		day(2025, 10, 7), day(2025, 10, 9),
+	}, dates)
+}
+
+func TestExpectedDatesIntervalStartsBeforeWindow(t *testing.T) {
+	// The goal started a week before the query window; the walk should
+	// land on the first occurrence inside [start, end) rather than
+	// re-running from reference every time.
+	meta := FrequencyMetadata{Interval: 3, Unit: "day"}
+	dates := ExpectedDates(FrequencyInterval, meta, day(2025, 9, 24), day(2025, 10, 1), day(2025, 10, 5))
+	assert.Equal(t, []time.Time{day(2025, 10, 3)}, dates)
+}
+
+func TestExpectedDatesUnknownFrequencyFallsBackToDaily(t *testing.T) {
+	dates := ExpectedDates("bogus", FrequencyMetadata{}, day(2025, 10, 1), day(2025, 10, 1), day(2025, 10, 4))
+	assert.Len(t, dates, 3)
+}
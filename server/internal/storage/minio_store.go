@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinioStore wraps a MinIO/AWS S3-compatible client.
+type MinioStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewMinioStoreFromEnv builds a MinioStore from STORAGE_ENDPOINT,
+// STORAGE_BUCKET, STORAGE_ACCESS_KEY, STORAGE_SECRET_KEY, and
+// STORAGE_USE_SSL.
+func NewMinioStoreFromEnv() (*MinioStore, error) {
+	endpoint := os.Getenv("STORAGE_ENDPOINT")
+	bucket := os.Getenv("STORAGE_BUCKET")
+	accessKey := os.Getenv("STORAGE_ACCESS_KEY")
+	secretKey := os.Getenv("STORAGE_SECRET_KEY")
+	useSSL, _ := strconv.ParseBool(os.Getenv("STORAGE_USE_SSL"))
+
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("storage: STORAGE_ENDPOINT and STORAGE_BUCKET are required")
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to create client: %w", err)
+	}
+
+	return &MinioStore{client: client, bucket: bucket}, nil
+}
+
+func (s *MinioStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *MinioStore) PresignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *MinioStore) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// NoopStore discards all operations. It lets main.go boot without S3
+// credentials configured, mirroring how the mock pubsub.Publisher is used
+// for local/emulator runs.
+type NoopStore struct{}
+
+func (NoopStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	return nil
+}
+
+func (NoopStore) PresignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "", nil
+}
+
+func (NoopStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package storage wraps an S3-compatible object store (MinIO, AWS S3, etc.)
+// used to hold photos/notes attached to progress entries.
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Store persists progress attachments in an S3-compatible object store.
+type Store interface {
+	// Put uploads body under key, sized size bytes, with the given content type.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// PresignedURL returns a time-limited URL for reading the object at key.
+	PresignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// Delete removes the object at key.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,57 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	"github.com/scosta/goal-app/internal/handlers"
+)
+
+// Enqueuer implements handlers.TaskEnqueuer on top of an asynq client and
+// the Firestore-backed StatusStore.
+type Enqueuer struct {
+	Client *asynq.Client
+	Status *StatusStore
+}
+
+func (e *Enqueuer) EnqueueRecomputeGoalStats(ctx context.Context, goalId string) (string, error) {
+	taskId := uuid.New().String()
+	task, err := NewRecomputeGoalStatsTask(goalId, taskId)
+	if err != nil {
+		return "", err
+	}
+	return e.enqueue(ctx, taskId, task)
+}
+
+func (e *Enqueuer) EnqueueRecomputeMonth(ctx context.Context, month string) (string, error) {
+	taskId := uuid.New().String()
+	task, err := NewRecomputeMonthTask(month, taskId)
+	if err != nil {
+		return "", err
+	}
+	return e.enqueue(ctx, taskId, task)
+}
+
+func (e *Enqueuer) enqueue(ctx context.Context, taskId string, task *asynq.Task) (string, error) {
+	if err := e.Status.Create(ctx, taskId); err != nil {
+		return "", err
+	}
+	if _, err := e.Client.EnqueueContext(ctx, task); err != nil {
+		return "", err
+	}
+	return taskId, nil
+}
+
+func (e *Enqueuer) TaskStatus(ctx context.Context, taskId string) (handlers.TaskStatus, error) {
+	rec, err := e.Status.Get(ctx, taskId)
+	if err != nil {
+		return handlers.TaskStatus{}, err
+	}
+	return handlers.TaskStatus{
+		Id:     rec.Id,
+		Status: string(rec.Status),
+		Error:  rec.Error,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Status is the lifecycle state of a recompute job.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// TaskRecord is the Firestore-backed status document clients poll via
+// GET /api/tasks/{taskId}.
+type TaskRecord struct {
+	Id        string    `firestore:"id" json:"id"`
+	Status    Status    `firestore:"status" json:"status"`
+	Error     string    `firestore:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// StatusStore persists TaskRecords in Firestore.
+type StatusStore struct {
+	Fs   *firestore.Client
+	Coll string
+}
+
+// Create records a freshly-enqueued task as pending.
+func (s *StatusStore) Create(ctx context.Context, taskId string) error {
+	now := time.Now().UTC()
+	_, err := s.Fs.Collection(s.Coll).Doc(taskId).Set(ctx, TaskRecord{
+		Id:        taskId,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	return err
+}
+
+func (s *StatusStore) MarkRunning(ctx context.Context, taskId string) error {
+	return s.update(ctx, taskId, StatusRunning, "")
+}
+
+func (s *StatusStore) MarkCompleted(ctx context.Context, taskId string) error {
+	return s.update(ctx, taskId, StatusCompleted, "")
+}
+
+func (s *StatusStore) MarkFailed(ctx context.Context, taskId string, cause error) error {
+	return s.update(ctx, taskId, StatusFailed, cause.Error())
+}
+
+func (s *StatusStore) update(ctx context.Context, taskId string, status Status, errMsg string) error {
+	updates := []firestore.Update{
+		{Path: "status", Value: status},
+		{Path: "updatedAt", Value: time.Now().UTC()},
+	}
+	if errMsg != "" {
+		updates = append(updates, firestore.Update{Path: "error", Value: errMsg})
+	}
+	_, err := s.Fs.Collection(s.Coll).Doc(taskId).Update(ctx, updates)
+	return err
+}
+
+// Get reads a task's current status.
+func (s *StatusStore) Get(ctx context.Context, taskId string) (TaskRecord, error) {
+	doc, err := s.Fs.Collection(s.Coll).Doc(taskId).Get(ctx)
+	if err != nil {
+		return TaskRecord{}, err
+	}
+	var rec TaskRecord
+	err = doc.DataTo(&rec)
+	return rec, err
+}
@@ -0,0 +1,63 @@
+// Package tasks enqueues and processes asynchronous recompute jobs on a
+// Redis-backed asynq queue, so POST /goals/{goalId}/recompute and
+// POST /progress/recompute don't block the request while Firestore is
+// scanned and rewritten.
+package tasks
+
+import (
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// TypeRecomputeGoalStats recalculates one goal's streaks and month and
+	// year-to-date minutes in goal_stats. It does not rewrite the stored
+	// TargetMet of the goal's progress entries.
+	TypeRecomputeGoalStats = "recompute:goal_stats"
+	// TypeRecomputeMonth recalculates monthly aggregates across all goals.
+	TypeRecomputeMonth = "recompute:month"
+	// TypeCheckMissedProgress scans every goal for one that expected
+	// activity yesterday (in the goal's timezone) but has no matching
+	// progress entry, publishing progress.missed for each one found.
+	TypeCheckMissedProgress = "schedule:check_missed_progress"
+)
+
+// RecomputeGoalStatsPayload is the task payload for TypeRecomputeGoalStats.
+type RecomputeGoalStatsPayload struct {
+	GoalId string `json:"goalId"`
+	TaskId string `json:"taskId"`
+}
+
+// RecomputeMonthPayload is the task payload for TypeRecomputeMonth.
+type RecomputeMonthPayload struct {
+	Month  string `json:"month"` // YYYY-MM
+	TaskId string `json:"taskId"`
+}
+
+// NewRecomputeGoalStatsTask builds a TypeRecomputeGoalStats task.
+func NewRecomputeGoalStatsTask(goalId, taskId string) (*asynq.Task, error) {
+	payload, err := json.Marshal(RecomputeGoalStatsPayload{GoalId: goalId, TaskId: taskId})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeRecomputeGoalStats, payload), nil
+}
+
+// NewRecomputeMonthTask builds a TypeRecomputeMonth task.
+func NewRecomputeMonthTask(month, taskId string) (*asynq.Task, error) {
+	payload, err := json.Marshal(RecomputeMonthPayload{Month: month, TaskId: taskId})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeRecomputeMonth, payload), nil
+}
+
+// NewCheckMissedProgressTask builds a TypeCheckMissedProgress task. It
+// carries no payload: the check always evaluates "yesterday" relative to
+// when the worker picks up the task, since asynq.NewScheduler bakes a
+// periodic task's payload in at registration time rather than at each
+// firing.
+func NewCheckMissedProgressTask() (*asynq.Task, error) {
+	return asynq.NewTask(TypeCheckMissedProgress, nil), nil
+}
@@ -0,0 +1,205 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/hibiken/asynq"
+	"google.golang.org/api/iterator"
+
+	"github.com/scosta/goal-app/internal/consumer"
+	"github.com/scosta/goal-app/internal/handlers"
+	openapi "github.com/scosta/goal-app/internal/models"
+	"github.com/scosta/goal-app/internal/pubsub"
+	"github.com/scosta/goal-app/internal/schedule"
+)
+
+// Worker processes recompute jobs from the asynq queue, reusing the same
+// calculation helpers the HTTP handlers and pub/sub consumer use so all
+// three paths stay consistent.
+type Worker struct {
+	Fs           *firestore.Client
+	GoalsColl    string
+	ProgressColl string
+	StatsColl    string
+	Status       *StatusStore
+
+	// Pub publishes progress.missed events found by the periodic
+	// check-missed-progress task. Nil (or a Publisher with no topic) is
+	// fine for tests that never exercise that task.
+	Pub *pubsub.Publisher
+}
+
+// Mux builds the asynq dispatch table for cmd/worker.
+func (w *Worker) Mux() *asynq.ServeMux {
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(TypeRecomputeGoalStats, w.handleRecomputeGoalStats)
+	mux.HandleFunc(TypeRecomputeMonth, w.handleRecomputeMonth)
+	mux.HandleFunc(TypeCheckMissedProgress, w.handleCheckMissedProgress)
+	return mux
+}
+
+func (w *Worker) handleRecomputeGoalStats(ctx context.Context, t *asynq.Task) error {
+	var payload RecomputeGoalStatsPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+	return w.run(ctx, payload.TaskId, func(ctx context.Context) error {
+		return w.recomputeGoalStats(ctx, payload.GoalId)
+	})
+}
+
+func (w *Worker) handleRecomputeMonth(ctx context.Context, t *asynq.Task) error {
+	var payload RecomputeMonthPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+	return w.run(ctx, payload.TaskId, func(ctx context.Context) error {
+		return w.recomputeMonth(ctx, payload.Month)
+	})
+}
+
+// run wraps a job body with status bookkeeping so GET /tasks/{taskId}
+// reflects what actually happened.
+func (w *Worker) run(ctx context.Context, taskId string, body func(context.Context) error) error {
+	if err := w.Status.MarkRunning(ctx, taskId); err != nil {
+		return err
+	}
+	if err := body(ctx); err != nil {
+		_ = w.Status.MarkFailed(ctx, taskId, err)
+		return err
+	}
+	return w.Status.MarkCompleted(ctx, taskId)
+}
+
+// recomputeGoalStats rebuilds goal_stats/{goalId} on demand via
+// consumer.ComputeGoalStats - the exact same streak/MTD/YTD computation
+// internal/consumer's event-driven refreshGoalStats writes after a progress
+// event, so this endpoint can't leave the materialized doc in a shape the
+// consumer doesn't recognize or stomp it with different numbers.
+func (w *Worker) recomputeGoalStats(ctx context.Context, goalId string) error {
+	if _, err := w.Fs.Collection(w.GoalsColl).Doc(goalId).Get(ctx); err != nil {
+		return fmt.Errorf("fetch goal: %w", err)
+	}
+
+	stats, err := consumer.ComputeGoalStats(ctx, w.Fs, w.ProgressColl, goalId)
+	if err != nil {
+		return err
+	}
+	_, err = w.Fs.Collection(w.StatsColl).Doc(goalId).Set(ctx, stats)
+	return err
+}
+
+func (w *Worker) recomputeMonth(ctx context.Context, month string) error {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return fmt.Errorf("parse month: %w", err)
+	}
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	iter := w.Fs.Collection(w.ProgressColl).
+		Where("date", ">=", start).
+		Where("date", "<", end).
+		Documents(ctx)
+	var progress []openapi.Progress
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			break
+		}
+		var p openapi.Progress
+		if err := doc.DataTo(&p); err == nil {
+			progress = append(progress, p)
+		}
+	}
+
+	totalMinutes, daysTracked := handlers.CalculateMonthlyAggregates(progress)
+	_, err = w.Fs.Collection(w.StatsColl).Doc("month_"+month).Set(ctx, map[string]interface{}{
+		"month":        month,
+		"totalMinutes": totalMinutes,
+		"daysTracked":  daysTracked,
+	})
+	return err
+}
+
+func (w *Worker) handleCheckMissedProgress(ctx context.Context, t *asynq.Task) error {
+	return w.checkMissedProgress(ctx, time.Now().UTC())
+}
+
+// checkMissedProgress scans every goal for "yesterday" (in the goal's own
+// timezone, relative to now) and publishes progress.missed for any goal
+// whose schedule expected activity that day but has no matching progress
+// entry. It isn't wrapped in w.run/Status: it's a periodic sweep with no
+// caller polling a taskId, not a user-triggered job.
+func (w *Worker) checkMissedProgress(ctx context.Context, now time.Time) error {
+	iter := w.Fs.Collection(w.GoalsColl).Documents(ctx)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("list goals: %w", err)
+		}
+
+		var goal openapi.Goal
+		if err := doc.DataTo(&goal); err != nil {
+			continue
+		}
+
+		if err := w.checkGoalMissedProgress(ctx, goal, now); err != nil {
+			return fmt.Errorf("check goal %s: %w", goal.Id, err)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) checkGoalMissedProgress(ctx context.Context, goal openapi.Goal, now time.Time) error {
+	loc := goalTimezone(goal.Timezone)
+	localNow := now.In(loc)
+	yesterday := time.Date(localNow.Year(), localNow.Month(), localNow.Day()-1, 0, 0, 0, 0, loc)
+
+	expected := schedule.ExpectedDates(goal.Frequency, goal.FrequencyMetadata, goal.StartDate.Time, yesterday, yesterday.AddDate(0, 0, 1))
+	if len(expected) == 0 {
+		return nil // not an expected activity day for this goal
+	}
+
+	progressIter := w.Fs.Collection(w.ProgressColl).
+		Where("goalId", "==", goal.Id).
+		Where("date", ">=", yesterday).
+		Where("date", "<", yesterday.AddDate(0, 0, 1)).
+		Limit(1).
+		Documents(ctx)
+	_, err := progressIter.Next()
+	if err == nil {
+		return nil // progress was logged; nothing missed
+	}
+	if err != iterator.Done {
+		return fmt.Errorf("query progress for goal %s: %w", goal.Id, err)
+	}
+
+	return w.Pub.Publish(ctx, map[string]interface{}{
+		"type": "progress.missed",
+		"payload": map[string]interface{}{
+			"goalId": goal.Id,
+			"date":   yesterday,
+		},
+	})
+}
+
+// goalTimezone resolves an IANA timezone name to a *time.Location, falling
+// back to UTC if it's unset or invalid.
+func goalTimezone(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}